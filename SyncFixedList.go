@@ -4,6 +4,9 @@ import "sync"
 
 /* FixedList */
 
+// SyncFixedList is a FixedList guarded by a sync.Mutex, so it can be shared between goroutines.
+// Nodes returned by First, Last and Remove must not be traversed or modified without holding the lock;
+// prefer the value-returning methods, Range or WithLock.
 type SyncFixedList[T any] struct {
 	sync.Mutex
 	l FixedList[T]
@@ -25,6 +28,14 @@ func NewSyncFixedListConfigurable[T any](configLoader func() int) *SyncFixedList
 	}
 }
 
+// WithLock calls f with the underlying list while holding the lock.
+// f must not keep the list or its nodes after it returns.
+func (me *SyncFixedList[T]) WithLock(f func(l *FixedList[T])) {
+	me.Lock()
+	defer me.Unlock()
+	f(&me.l)
+}
+
 func (me *SyncFixedList[T]) AddFirst(v T) {
 	me.Lock()
 	defer me.Unlock()
@@ -37,6 +48,35 @@ func (me *SyncFixedList[T]) AddLast(v T) {
 	me.l.AddLast(v)
 }
 
+// OnEvict sets fn to be called with every element dropped to stay within maxSize, like FixedList.OnEvict.
+// fn is called with the lock held, so it must not call methods of the list.
+func (me *SyncFixedList[T]) OnEvict(fn func(v T)) {
+	me.Lock()
+	defer me.Unlock()
+	me.l.OnEvict(fn)
+}
+
+// AddMany adds the elements of vs to the end of the list at once.
+func (me *SyncFixedList[T]) AddMany(vs []T) {
+	me.Lock()
+	defer me.Unlock()
+	for _, v := range vs {
+		me.l.AddLast(v)
+	}
+}
+
+func (me *SyncFixedList[T]) PopFirst() (result T, ok bool) {
+	me.Lock()
+	defer me.Unlock()
+	return nodeValue(me.l.PopFirst())
+}
+
+func (me *SyncFixedList[T]) PopLast() (result T, ok bool) {
+	me.Lock()
+	defer me.Unlock()
+	return nodeValue(me.l.PopLast())
+}
+
 func (me *SyncFixedList[T]) ToSlice() []T {
 	me.Lock()
 	defer me.Unlock()
@@ -44,17 +84,54 @@ func (me *SyncFixedList[T]) ToSlice() []T {
 }
 
 func (me *SyncFixedList[T]) Count() int {
+	me.Lock()
+	defer me.Unlock()
 	return me.l.l.count
 }
 
 func (me *SyncFixedList[T]) First() *Node[T] {
+	me.Lock()
+	defer me.Unlock()
 	return me.l.l.front
 }
 
 func (me *SyncFixedList[T]) Last() *Node[T] {
+	me.Lock()
+	defer me.Unlock()
 	return me.l.l.back
 }
 
+func (me *SyncFixedList[T]) FirstValue() (result T, ok bool) {
+	me.Lock()
+	defer me.Unlock()
+	return nodeValue(me.l.l.front)
+}
+
+func (me *SyncFixedList[T]) LastValue() (result T, ok bool) {
+	me.Lock()
+	defer me.Unlock()
+	return nodeValue(me.l.l.back)
+}
+
+// At returns the element at the given index. Negative indices are counted from the end of the list.
+func (me *SyncFixedList[T]) At(i int) (result T, ok bool) {
+	me.Lock()
+	defer me.Unlock()
+	return nodeValue(me.l.l.At(i))
+}
+
+// Range calls fn with every element from the first to the last while holding the lock, until fn returns false.
+// fn must not call other methods of the list, or it deadlocks.
+func (me *SyncFixedList[T]) Range(fn func(v T) bool) {
+	me.Lock()
+	defer me.Unlock()
+	for n := me.l.l.front; n != nil; n = n.next {
+		if !fn(n.Value) {
+			return
+		}
+	}
+}
+
 func (me *SyncFixedList[T]) Clear() {
 	me.Lock()
 	defer me.Unlock()
@@ -68,14 +145,28 @@ func (me *SyncFixedList[T]) Remove(n *Node[T]) *Node[T] {
 }
 
 func (me *SyncFixedList[T]) MaxSize() int {
+	me.Lock()
+	defer me.Unlock()
 	return me.l.maxSize()
 }
 
 func (me *SyncFixedList[T]) Full() bool {
-	return me.l.l.count == me.l.maxSize()
+	me.Lock()
+	defer me.Unlock()
+	return me.l.Full()
 }
 
 // Filled is the same as Full except it returns false if the buffer's size is 0
 func (me *SyncFixedList[T]) Filled() bool {
-	return me.l.l.count != 0 && me.Full()
+	me.Lock()
+	defer me.Unlock()
+	return me.l.Filled()
+}
+
+// nodeValue returns the value of n, and false if n is nil.
+func nodeValue[T any](n *Node[T]) (result T, ok bool) {
+	if n == nil {
+		return
+	}
+	return n.Value, true
 }
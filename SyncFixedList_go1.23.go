@@ -0,0 +1,26 @@
+//go:build go1.23
+// +build go1.23
+
+package quantainer
+
+import "iter"
+
+// Values iterates over the elements from the first to the last, holding the lock until the loop ends.
+// The loop body must not call other methods of the list, or it deadlocks.
+func (me *SyncFixedList[T]) Values() iter.Seq[T] {
+	return me.Range
+}
+
+// All is Values with the index of each element, holding the lock until the loop ends.
+func (me *SyncFixedList[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		me.Range(func(v T) bool {
+			if !yield(i, v) {
+				return false
+			}
+			i++
+			return true
+		})
+	}
+}
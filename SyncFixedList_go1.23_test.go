@@ -0,0 +1,37 @@
+//go:build go1.23
+// +build go1.23
+
+package quantainer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSyncFixedList_Iterators(t *testing.T) {
+	l := NewSyncFixedList[int](3)
+	l.AddMany([]int{1, 2, 3, 4})
+
+	var values []int
+	for v := range l.Values() {
+		if l.TryLock() {
+			t.Fatal("Expected the lock to be held during iteration")
+		}
+		values = append(values, v)
+	}
+	if !reflect.DeepEqual(values, []int{2, 3, 4}) {
+		t.Fatalf("Values want [2 3 4] got %v", values)
+	}
+	for i, v := range l.All() {
+		if v != i+2 {
+			t.Fatalf("All want %d at %d got %d", i+2, i, v)
+		}
+		if i == 1 {
+			break
+		}
+	}
+	if !l.TryLock() {
+		t.Fatal("Expected the lock to be released after breaking out of the loop")
+	}
+	l.Unlock()
+}
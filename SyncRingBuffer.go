@@ -0,0 +1,287 @@
+package quantainer
+
+import (
+	"sync"
+
+	"golang.org/x/exp/constraints"
+)
+
+/* RingBuffer */
+
+// SyncRingBuffer is a RingBuffer guarded by a sync.RWMutex, so it can be shared between goroutines.
+// Methods that only read the buffer take the read lock and may run concurrently.
+// Elements are returned by value, since pointers into the buffer would be overwritten after the lock is released.
+// Use WithLock or WithRLock to run several operations atomically.
+type SyncRingBuffer[T any] struct {
+	sync.RWMutex
+	rb RingBuffer[T]
+}
+
+func NewSyncRingBuffer[T any](size int) *SyncRingBuffer[T] {
+	return &SyncRingBuffer[T]{
+		rb: NewRingBuffer[T](size),
+	}
+}
+
+// NewSyncRingBufferConfigurable creates a new ring buffer with a configurable maximum size.
+// If maxSize is changed at runtime, the buffer will not resize until you add an element.
+func NewSyncRingBufferConfigurable[T any](maxSize func() int) *SyncRingBuffer[T] {
+	return &SyncRingBuffer[T]{
+		rb: *NewRingBufferConfigurable[T](maxSize),
+	}
+}
+
+// WithLock calls f with the underlying buffer while holding the write lock.
+// f must not keep the buffer or pointers into it after it returns.
+func (me *SyncRingBuffer[T]) WithLock(f func(rb *RingBuffer[T])) {
+	me.Lock()
+	defer me.Unlock()
+	f(&me.rb)
+}
+
+// WithRLock calls f with the underlying buffer while holding the read lock. f must not modify the buffer.
+func (me *SyncRingBuffer[T]) WithRLock(f func(rb *RingBuffer[T])) {
+	me.RLock()
+	defer me.RUnlock()
+	f(&me.rb)
+}
+
+func (me *SyncRingBuffer[T]) AddLast(v T) {
+	me.Lock()
+	defer me.Unlock()
+	me.rb.AddLast(v)
+}
+
+// OnEvict sets fn to be called with every element AddLast drops, like RingBuffer.OnEvict.
+// fn is called with the lock held, so it must not call methods of the buffer.
+func (me *SyncRingBuffer[T]) OnEvict(fn func(v T)) {
+	me.Lock()
+	defer me.Unlock()
+	me.rb.OnEvict(fn)
+}
+
+func (me *SyncRingBuffer[T]) PopFirst() (result T, ok bool) {
+	me.Lock()
+	defer me.Unlock()
+	return deref(me.rb.PopFirst())
+}
+
+func (me *SyncRingBuffer[T]) PopLast() (result T, ok bool) {
+	me.Lock()
+	defer me.Unlock()
+	return deref(me.rb.PopLast())
+}
+
+func (me *SyncRingBuffer[T]) First() (result T, ok bool) {
+	me.RLock()
+	defer me.RUnlock()
+	return deref(me.rb.First())
+}
+
+func (me *SyncRingBuffer[T]) Last() (result T, ok bool) {
+	me.RLock()
+	defer me.RUnlock()
+	return deref(me.rb.Last())
+}
+
+func (me *SyncRingBuffer[T]) At(i int) (result T, ok bool) {
+	me.RLock()
+	defer me.RUnlock()
+	return deref(me.rb.At(i))
+}
+
+func (me *SyncRingBuffer[T]) ToSlice() []T {
+	me.RLock()
+	defer me.RUnlock()
+	return me.rb.ToSlice()
+}
+
+func (me *SyncRingBuffer[T]) Count() int {
+	me.RLock()
+	defer me.RUnlock()
+	return me.rb.count
+}
+
+func (me *SyncRingBuffer[T]) MaxSize() int {
+	me.RLock()
+	defer me.RUnlock()
+	return me.rb.maxSize()
+}
+
+func (me *SyncRingBuffer[T]) Full() bool {
+	me.RLock()
+	defer me.RUnlock()
+	return me.rb.Full()
+}
+
+// Filled is the same as Full except it returns false if the buffer's size is 0
+func (me *SyncRingBuffer[T]) Filled() bool {
+	me.RLock()
+	defer me.RUnlock()
+	return me.rb.Filled()
+}
+
+func (me *SyncRingBuffer[T]) Clear() {
+	me.Lock()
+	defer me.Unlock()
+	me.rb.Clear()
+}
+
+/* SortedRingBuffer */
+
+// SyncSortedRingBuffer is a SortedRingBuffer guarded by a sync.RWMutex, like SyncRingBuffer.
+type SyncSortedRingBuffer[T constraints.Ordered] struct {
+	sync.RWMutex
+	rb SortedRingBuffer[T]
+}
+
+func NewSyncSortedRingBuffer[T constraints.Ordered](size int) *SyncSortedRingBuffer[T] {
+	return &SyncSortedRingBuffer[T]{
+		rb: *NewSortedRingBuffer[T](size),
+	}
+}
+
+// NewSyncSortedRingBufferConfigurable creates a new sorted ring buffer with a configurable maximum size.
+// If maxSize is changed at runtime, the buffer will not resize until you add an element.
+func NewSyncSortedRingBufferConfigurable[T constraints.Ordered](maxSize func() int) *SyncSortedRingBuffer[T] {
+	return &SyncSortedRingBuffer[T]{
+		rb: *NewSortedRingBufferConfigurable[T](maxSize),
+	}
+}
+
+// WithLock calls f with the underlying buffer while holding the write lock.
+// f must not keep the buffer or pointers into it after it returns.
+func (me *SyncSortedRingBuffer[T]) WithLock(f func(rb *SortedRingBuffer[T])) {
+	me.Lock()
+	defer me.Unlock()
+	f(&me.rb)
+}
+
+// WithRLock calls f with the underlying buffer while holding the read lock. f must not modify the buffer.
+func (me *SyncSortedRingBuffer[T]) WithRLock(f func(rb *SortedRingBuffer[T])) {
+	me.RLock()
+	defer me.RUnlock()
+	f(&me.rb)
+}
+
+func (me *SyncSortedRingBuffer[T]) AddLast(v T) {
+	me.Lock()
+	defer me.Unlock()
+	me.rb.AddLast(v)
+}
+
+// OnEvict sets fn to be called with every element AddLast drops, like SortedRingBuffer.OnEvict.
+// fn is called with the lock held, so it must not call methods of the buffer.
+func (me *SyncSortedRingBuffer[T]) OnEvict(fn func(v T)) {
+	me.Lock()
+	defer me.Unlock()
+	me.rb.OnEvict(fn)
+}
+
+func (me *SyncSortedRingBuffer[T]) PopFirst() (result T, ok bool) {
+	me.Lock()
+	defer me.Unlock()
+	return deref(me.rb.PopFirst())
+}
+
+func (me *SyncSortedRingBuffer[T]) First() (result T, ok bool) {
+	me.RLock()
+	defer me.RUnlock()
+	return deref(me.rb.First())
+}
+
+func (me *SyncSortedRingBuffer[T]) Last() (result T, ok bool) {
+	me.RLock()
+	defer me.RUnlock()
+	return deref(me.rb.Last())
+}
+
+func (me *SyncSortedRingBuffer[T]) At(i int) (result T, ok bool) {
+	me.RLock()
+	defer me.RUnlock()
+	return deref(me.rb.At(i))
+}
+
+func (me *SyncSortedRingBuffer[T]) ToSlice() []T {
+	me.RLock()
+	defer me.RUnlock()
+	return me.rb.ToSlice()
+}
+
+// SortedSlice returns a sorted slice of the elements in the buffer.
+// If cachedSlice is provided, it will be reused if large enough.
+func (me *SyncSortedRingBuffer[T]) SortedSlice(cachedSlice []T) []T {
+	me.RLock()
+	defer me.RUnlock()
+	return me.rb.SortedSlice(cachedSlice)
+}
+
+// Select returns the k-th smallest element (0-based) in O(log n).
+// ok is false if k is out of range.
+func (me *SyncSortedRingBuffer[T]) Select(k int) (result T, ok bool) {
+	me.RLock()
+	defer me.RUnlock()
+	return me.rb.Select(k)
+}
+
+// Rank returns the number of elements strictly less than v in O(log n).
+func (me *SyncSortedRingBuffer[T]) Rank(v T) int {
+	me.RLock()
+	defer me.RUnlock()
+	return me.rb.Rank(v)
+}
+
+// SyncSortedRingBufferPercentile returns the p-th percentile (0 <= p <= 100) of the elements in O(log n),
+// or NaN if the buffer is empty.
+func SyncSortedRingBufferPercentile[T Number](me *SyncSortedRingBuffer[T], p float64, interpolation Interpolation) float64 {
+	me.RLock()
+	defer me.RUnlock()
+	return SortedRingBufferPercentile(&me.rb, p, interpolation)
+}
+
+// SyncSortedRingBufferMedian returns the median of the elements, or NaN if the buffer is empty.
+func SyncSortedRingBufferMedian[T Number](me *SyncSortedRingBuffer[T]) float64 {
+	me.RLock()
+	defer me.RUnlock()
+	return SortedRingBufferMedian(&me.rb)
+}
+
+func (me *SyncSortedRingBuffer[T]) Count() int {
+	me.RLock()
+	defer me.RUnlock()
+	return me.rb.Count()
+}
+
+func (me *SyncSortedRingBuffer[T]) MaxSize() int {
+	me.RLock()
+	defer me.RUnlock()
+	return me.rb.MaxSize()
+}
+
+// Full returns true if the buffer's max size is reached.
+func (me *SyncSortedRingBuffer[T]) Full() bool {
+	me.RLock()
+	defer me.RUnlock()
+	return me.rb.Full()
+}
+
+// Filled is the same as Full except it returns false if the buffer's size is 0
+func (me *SyncSortedRingBuffer[T]) Filled() bool {
+	me.RLock()
+	defer me.RUnlock()
+	return me.rb.Filled()
+}
+
+func (me *SyncSortedRingBuffer[T]) Clear() {
+	me.Lock()
+	defer me.Unlock()
+	me.rb.Clear()
+}
+
+// deref returns the value p points to, and false if p is nil.
+func deref[T any](p *T) (result T, ok bool) {
+	if p == nil {
+		return
+	}
+	return *p, true
+}
@@ -0,0 +1,48 @@
+//go:build go1.23
+// +build go1.23
+
+package quantainer
+
+import "iter"
+
+// Values iterates over the elements from the oldest to the newest, holding the read lock until the loop ends.
+// The loop body must not call any method of the buffer, not even a reading one: re-entering the read lock
+// deadlocks as soon as a writer is waiting for it.
+func (me *SyncRingBuffer[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		me.RLock()
+		defer me.RUnlock()
+		me.rb.Values()(yield)
+	}
+}
+
+// All is Values with the index of each element, holding the read lock until the loop ends.
+// Like Values, the loop body must not call any method of the buffer.
+func (me *SyncRingBuffer[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		me.RLock()
+		defer me.RUnlock()
+		me.rb.All()(yield)
+	}
+}
+
+// Values iterates over the elements from the oldest to the newest, holding the read lock until the loop ends.
+// The loop body must not call any method of the buffer, not even a reading one: re-entering the read lock
+// deadlocks as soon as a writer is waiting for it.
+func (me *SyncSortedRingBuffer[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		me.RLock()
+		defer me.RUnlock()
+		me.rb.Values()(yield)
+	}
+}
+
+// All is Values with the index of each element, holding the read lock until the loop ends.
+// Like Values, the loop body must not call any method of the buffer.
+func (me *SyncSortedRingBuffer[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		me.RLock()
+		defer me.RUnlock()
+		me.rb.All()(yield)
+	}
+}
@@ -0,0 +1,61 @@
+//go:build go1.23
+// +build go1.23
+
+package quantainer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSyncRingBuffer_Iterators(t *testing.T) {
+	rb := NewSyncRingBuffer[int](3)
+	for i := 1; i <= 4; i++ {
+		rb.AddLast(i)
+	}
+
+	var values []int
+	for v := range rb.Values() {
+		if rb.TryLock() {
+			t.Fatal("Expected the read lock to be held during iteration")
+		}
+		values = append(values, v)
+	}
+	if !reflect.DeepEqual(values, []int{2, 3, 4}) {
+		t.Fatalf("Values want [2 3 4] got %v", values)
+	}
+	for i, v := range rb.All() {
+		if v != i+2 {
+			t.Fatalf("All want %d at %d got %d", i+2, i, v)
+		}
+		break
+	}
+	if !rb.TryLock() {
+		t.Fatal("Expected the read lock to be released after breaking out of the loop")
+	}
+	rb.Unlock()
+}
+
+func TestSyncSortedRingBuffer_Iterators(t *testing.T) {
+	rb := NewSyncSortedRingBuffer[int](3)
+	for _, v := range []int{3, 1, 2} {
+		rb.AddLast(v)
+	}
+
+	var values []int
+	for i, v := range rb.All() {
+		if rb.TryLock() {
+			t.Fatal("Expected the read lock to be held during iteration")
+		}
+		if i != len(values) {
+			t.Fatalf("All index want %d got %d", len(values), i)
+		}
+		values = append(values, v)
+	}
+	for v := range rb.Values() {
+		values = append(values, v)
+	}
+	if !reflect.DeepEqual(values, []int{3, 1, 2, 3, 1, 2}) {
+		t.Fatalf("want [3 1 2 3 1 2] got %v", values)
+	}
+}
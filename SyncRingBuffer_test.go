@@ -0,0 +1,169 @@
+package quantainer
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func ExampleSyncRingBuffer_AddLast() {
+	rb := NewSyncRingBuffer[int](3)
+	rb.AddLast(1)
+	rb.AddLast(2)
+	rb.AddLast(3)
+	rb.AddLast(4)
+	for _, v := range rb.ToSlice() {
+		fmt.Println(v)
+	}
+	// Output:
+	// 2
+	// 3
+	// 4
+}
+
+func ExampleSyncRingBuffer_WithLock() {
+	rb := NewSyncRingBuffer[int](3)
+	rb.AddLast(1)
+	rb.AddLast(2)
+	// pop and push back atomically
+	rb.WithLock(func(rb *RingBuffer[int]) {
+		v := *rb.PopFirst()
+		rb.AddLast(v * 10)
+	})
+	fmt.Println(rb.ToSlice())
+	// Output:
+	// [2 10]
+}
+
+func TestSyncRingBuffer_Values(t *testing.T) {
+	rb := NewSyncRingBuffer[int](3)
+	if _, ok := rb.First(); ok {
+		t.Fatal("Expected no first element in an empty buffer")
+	}
+	if _, ok := rb.PopLast(); ok {
+		t.Fatal("Expected nothing to pop from an empty buffer")
+	}
+	for i := 1; i <= 4; i++ {
+		rb.AddLast(i)
+	}
+	if v, ok := rb.First(); !ok || v != 2 {
+		t.Fatalf("First want 2 got %d (ok=%v)", v, ok)
+	}
+	if v, ok := rb.Last(); !ok || v != 4 {
+		t.Fatalf("Last want 4 got %d (ok=%v)", v, ok)
+	}
+	if v, ok := rb.At(-2); !ok || v != 3 {
+		t.Fatalf("At(-2) want 3 got %d (ok=%v)", v, ok)
+	}
+	if v, ok := rb.PopFirst(); !ok || v != 2 {
+		t.Fatalf("PopFirst want 2 got %d (ok=%v)", v, ok)
+	}
+	if v, ok := rb.PopLast(); !ok || v != 4 {
+		t.Fatalf("PopLast want 4 got %d (ok=%v)", v, ok)
+	}
+	if rb.Count() != 1 || rb.Full() || rb.Filled() || rb.MaxSize() != 3 {
+		t.Fatalf("Count/Full/Filled/MaxSize want 1/false/false/3 got %d/%v/%v/%d", rb.Count(), rb.Full(), rb.Filled(), rb.MaxSize())
+	}
+	rb.Clear()
+	if rb.Count() != 0 {
+		t.Fatalf("Count after Clear want 0 got %d", rb.Count())
+	}
+}
+
+func TestSyncSortedRingBuffer(t *testing.T) {
+	size := 4
+	rb := NewSyncSortedRingBufferConfigurable[int](func() int { return size })
+	for _, v := range []int{5, 1, 4, 2, 3} {
+		rb.AddLast(v)
+	}
+	if got := rb.SortedSlice(nil); fmt.Sprint(got) != "[1 2 3 4]" {
+		t.Fatalf("SortedSlice want [1 2 3 4] got %v", got)
+	}
+	if v, ok := rb.Select(3); !ok || v != 4 {
+		t.Fatalf("Select(3) want 4 got %d (ok=%v)", v, ok)
+	}
+	if r := rb.Rank(3); r != 2 {
+		t.Fatalf("Rank(3) want 2 got %d", r)
+	}
+	if m := SyncSortedRingBufferMedian(rb); m != 2.5 {
+		t.Fatalf("Median want 2.5 got %v", m)
+	}
+	if p := SyncSortedRingBufferPercentile(rb, 100, InterpolationLower); p != 4 {
+		t.Fatalf("Percentile(100) want 4 got %v", p)
+	}
+	if v, ok := rb.PopFirst(); !ok || v != 1 {
+		t.Fatalf("PopFirst want 1 got %d (ok=%v)", v, ok)
+	}
+
+	size = 2
+	rb.AddLast(0)
+	if got := rb.ToSlice(); fmt.Sprint(got) != "[3 0]" || rb.Count() != 2 || !rb.Filled() || rb.MaxSize() != 2 {
+		t.Fatalf("After shrinking want [3 0] got %v", got)
+	}
+	rb.WithRLock(func(rb *SortedRingBuffer[int]) {
+		if first, last := *rb.First(), *rb.Last(); first != 3 || last != 0 {
+			t.Fatalf("First/Last want 3/0 got %d/%d", first, last)
+		}
+	})
+}
+
+// Writers and readers share the buffers concurrently; run with -race.
+func TestSyncRingBuffer_Concurrent(t *testing.T) {
+	rb := NewSyncRingBuffer[int](16)
+	srb := NewSyncSortedRingBuffer[int](16)
+	var wg sync.WaitGroup
+	for w := 0; w < 2; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				rb.AddLast(i)
+				srb.AddLast(w*1000 + i)
+				if i%10 == 0 {
+					rb.PopFirst()
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < 2; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if s := rb.ToSlice(); len(s) > 16 {
+					t.Errorf("ToSlice returned %d elements", len(s))
+					return
+				}
+				if n := srb.Count(); n > 16 {
+					t.Errorf("Count returned %d", n)
+					return
+				}
+				SyncSortedRingBufferMedian(srb)
+				rb.WithRLock(func(rb *RingBuffer[int]) {
+					if rb.Count() > 0 && rb.First() == nil {
+						t.Error("inconsistent buffer")
+					}
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	if srb.Count() != 16 || !srb.Full() {
+		t.Fatalf("Expected a full sorted buffer, got %d", srb.Count())
+	}
+}
+
+func TestSyncRingBuffer_OnEvict(t *testing.T) {
+	rb := NewSyncRingBuffer[int](2)
+	srb := NewSyncSortedRingBuffer[int](2)
+	var evicted, sortedEvicted []int
+	rb.OnEvict(func(v int) { evicted = append(evicted, v) })
+	srb.OnEvict(func(v int) { sortedEvicted = append(sortedEvicted, v) })
+	for _, v := range []int{3, 1, 2} {
+		rb.AddLast(v)
+		srb.AddLast(v)
+	}
+	if fmt.Sprint(evicted) != "[3]" || fmt.Sprint(sortedEvicted) != "[3]" {
+		t.Fatalf("evicted want [3] got %v and %v", evicted, sortedEvicted)
+	}
+}
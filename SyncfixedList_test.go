@@ -2,6 +2,7 @@ package quantainer
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 )
 
@@ -58,3 +59,96 @@ func TestSyncFixedList_Filled(t *testing.T) {
 		t.Fatalf("at capacity Filled want true")
 	}
 }
+
+func TestSyncFixedList_Values(t *testing.T) {
+	l := NewSyncFixedList[int](3)
+	if _, ok := l.FirstValue(); ok {
+		t.Fatalf("empty FirstValue want ok=false")
+	}
+	if _, ok := l.PopLast(); ok {
+		t.Fatalf("empty PopLast want ok=false")
+	}
+	l.AddMany([]int{1, 2, 3, 4})
+	if v, ok := l.FirstValue(); !ok || v != 2 {
+		t.Fatalf("FirstValue want 2 got %d (ok=%v)", v, ok)
+	}
+	if v, ok := l.LastValue(); !ok || v != 4 {
+		t.Fatalf("LastValue want 4 got %d (ok=%v)", v, ok)
+	}
+	if v, ok := l.At(1); !ok || v != 3 {
+		t.Fatalf("At(1) want 3 got %d (ok=%v)", v, ok)
+	}
+	if v, ok := l.At(-3); !ok || v != 2 {
+		t.Fatalf("At(-3) want 2 got %d (ok=%v)", v, ok)
+	}
+	if _, ok := l.At(3); ok {
+		t.Fatalf("At(3) want ok=false")
+	}
+
+	var values []int
+	l.Range(func(v int) bool {
+		values = append(values, v)
+		return v < 3
+	})
+	if fmt.Sprint(values) != "[2 3]" {
+		t.Fatalf("Range want [2 3] got %v", values)
+	}
+
+	if v, ok := l.PopFirst(); !ok || v != 2 {
+		t.Fatalf("PopFirst want 2 got %d (ok=%v)", v, ok)
+	}
+	if v, ok := l.PopLast(); !ok || v != 4 {
+		t.Fatalf("PopLast want 4 got %d (ok=%v)", v, ok)
+	}
+	l.WithLock(func(l *FixedList[int]) {
+		l.AddFirst(l.First().Value - 1)
+	})
+	if got := fmt.Sprint(l.ToSlice()); got != "[2 3]" || l.Count() != 2 || l.MaxSize() != 3 || l.Full() {
+		t.Fatalf("want [2 3] with 2 of 3 elements, got %s", got)
+	}
+}
+
+// Writers and readers share the list concurrently; run with -race.
+func TestSyncFixedList_Concurrent(t *testing.T) {
+	l := NewSyncFixedList[int](8)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			l.AddLast(i)
+			if i%3 == 0 {
+				l.PopFirst()
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			l.Count()
+			l.Filled()
+			l.FirstValue()
+			l.LastValue()
+			n := 0
+			l.Range(func(int) bool {
+				n++
+				return true
+			})
+			if n > 8 {
+				t.Errorf("Range saw %d elements", n)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+func TestSyncFixedList_OnEvict(t *testing.T) {
+	l := NewSyncFixedList[int](2)
+	var evicted []int
+	l.OnEvict(func(v int) { evicted = append(evicted, v) })
+	l.AddMany([]int{1, 2, 3, 4})
+	if fmt.Sprint(evicted) != "[1 2]" {
+		t.Fatalf("evicted want [1 2] got %v", evicted)
+	}
+}
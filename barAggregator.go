@@ -0,0 +1,257 @@
+package quantainer
+
+import "time"
+
+// LateTickPolicy decides what BarAggregator.AddAt does with a tick of a time bar interval that already closed,
+// i.e. older than the end of the last completed bar.
+type LateTickPolicy int
+
+const (
+	// LateTickDrop drops the tick, and AddAt returns false. This is the default.
+	LateTickDrop LateTickPolicy = iota
+	// LateTickFold adds the tick to the in-progress bar, or opens one for the interval after the last
+	// completed bar, as if it arrived at the end of the last completed bar.
+	LateTickFold
+)
+
+type (
+	// Bar is an OHLCV bar (candle).
+	Bar struct {
+		// Start is the start of the interval for time bars, and the time of the first tick otherwise.
+		Start time.Time
+		// End is the end of the interval for time bars, and the time of the last tick otherwise.
+		End                    time.Time
+		Open, High, Low, Close float64
+		Volume                 float64 // sum of the tick sizes
+		Notional               float64 // sum of price * size, so Notional / Volume is the VWAP of the bar
+		Ticks                  int
+	}
+
+	// BarSpec decides when a bar closes. Exactly one of its fields must be set; use the TimeBars, TickBars,
+	// VolumeBars and DollarBars helpers.
+	BarSpec struct {
+		Interval time.Duration // time bars: a bar per interval, aligned to multiples of it since the zero time
+		Ticks    int           // tick bars: a bar per Ticks ticks
+		Volume   float64       // volume bars: a bar once the volume reaches Volume
+		Dollars  float64       // dollar bars: a bar once the notional reaches Dollars
+	}
+
+	// BarAggregator builds OHLCV bars from ticks and stores the completed ones in a RingBuffer.
+	// Volume and dollar bars never split a tick, so the tick that reaches the threshold closes the bar with it.
+	// Time bars close when a tick of a later interval arrives, or on Advance; ticks older than the
+	// in-progress bar are added to it, and ticks of intervals that already closed are handled according
+	// to the LateTickPolicy, so bars are always emitted in time order.
+	BarAggregator struct {
+		spec    BarSpec
+		bars    RingBuffer[Bar]
+		cur     Bar
+		open    bool // whether cur is in progress
+		last    Bar  // the last completed bar
+		closed  bool // whether last is set
+		clock   Clock
+		onClose func(Bar)
+		gapFill bool
+		late    LateTickPolicy
+	}
+)
+
+// TimeBars closes a bar every interval.
+func TimeBars(interval time.Duration) BarSpec {
+	return BarSpec{Interval: interval}
+}
+
+// TickBars closes a bar every ticks ticks.
+func TickBars(ticks int) BarSpec {
+	return BarSpec{Ticks: ticks}
+}
+
+// VolumeBars closes a bar once its volume reaches volume.
+func VolumeBars(volume float64) BarSpec {
+	return BarSpec{Volume: volume}
+}
+
+// DollarBars closes a bar once its notional (price * size) reaches dollars.
+func DollarBars(dollars float64) BarSpec {
+	return BarSpec{Dollars: dollars}
+}
+
+func (me BarSpec) valid() bool {
+	n := 0
+	for _, set := range []bool{me.Interval > 0, me.Ticks > 0, me.Volume > 0, me.Dollars > 0} {
+		if set {
+			n++
+		}
+	}
+	return n == 1
+}
+
+// NewBarAggregator creates a BarAggregator keeping the last size completed bars.
+// It panics if spec does not have exactly one positive field.
+func NewBarAggregator(spec BarSpec, size int) *BarAggregator {
+	return newBarAggregator(spec, NewRingBuffer[Bar](size))
+}
+
+// NewBarAggregatorConfigurable creates a BarAggregator keeping a configurable number of completed bars.
+func NewBarAggregatorConfigurable(spec BarSpec, maxSize func() int) *BarAggregator {
+	return newBarAggregator(spec, *NewRingBufferConfigurable[Bar](maxSize))
+}
+
+func newBarAggregator(spec BarSpec, bars RingBuffer[Bar]) *BarAggregator {
+	if !spec.valid() {
+		panic("quantainer: BarSpec must have exactly one positive field")
+	}
+	return &BarAggregator{
+		spec:  spec,
+		bars:  bars,
+		clock: SystemClock,
+	}
+}
+
+// SetClock sets the clock Add and Advance read the current time from.
+func (me *BarAggregator) SetClock(clock Clock) {
+	me.clock = clock
+}
+
+// OnClose sets fn to be called with every completed bar, including gap-filling ones.
+func (me *BarAggregator) OnClose(fn func(bar Bar)) {
+	me.onClose = fn
+}
+
+// SetGapFill sets whether time bars emit flat bars for intervals without ticks, with all prices
+// at the previous close and no volume. It has no effect on other bars.
+func (me *BarAggregator) SetGapFill(gapFill bool) {
+	me.gapFill = gapFill
+}
+
+// SetLateTickPolicy sets what AddAt does with ticks of time bar intervals that already closed.
+func (me *BarAggregator) SetLateTickPolicy(policy LateTickPolicy) {
+	me.late = policy
+}
+
+// Add adds a tick at the current time of the clock.
+func (me *BarAggregator) Add(price, size float64) {
+	me.AddAt(price, size, me.clock.Now())
+}
+
+// AddAt adds a tick at tm, closing bars as needed.
+// A tick of a time bar interval that already closed is handled according to the LateTickPolicy.
+// It returns false if the tick was dropped.
+func (me *BarAggregator) AddAt(price, size float64, tm time.Time) bool {
+	if me.spec.Interval > 0 {
+		if me.closed && tm.Before(me.last.End) {
+			if me.late == LateTickDrop {
+				return false
+			}
+			tm = me.last.End
+		}
+		me.Advance(tm)
+	}
+	if !me.open {
+		me.cur = Bar{
+			Start: tm,
+			End:   tm,
+			Open:  price,
+			High:  price,
+			Low:   price,
+		}
+		if me.spec.Interval > 0 {
+			me.cur.Start = tm.Truncate(me.spec.Interval)
+			me.cur.End = me.cur.Start.Add(me.spec.Interval)
+		}
+		me.open = true
+	}
+
+	bar := &me.cur
+	if price > bar.High {
+		bar.High = price
+	}
+	if price < bar.Low {
+		bar.Low = price
+	}
+	bar.Close = price
+	bar.Volume += size
+	bar.Notional += price * size
+	bar.Ticks++
+	if me.spec.Interval > 0 {
+		return true
+	}
+	if tm.After(bar.End) {
+		bar.End = tm
+	}
+	if me.spec.Ticks > 0 && bar.Ticks >= me.spec.Ticks ||
+		me.spec.Volume > 0 && bar.Volume >= me.spec.Volume ||
+		me.spec.Dollars > 0 && bar.Notional >= me.spec.Dollars {
+		me.CloseCurrent()
+	}
+	return true
+}
+
+// Advance closes the in-progress time bar if its interval ended by now, and emits gap-filling bars
+// for the intervals ended since, if enabled. It does nothing for other bars.
+// At most as many gap-filling bars as the ring holds are emitted: the bars of older intervals would be
+// evicted right away, so they are skipped, and OnClose does not see them either.
+func (me *BarAggregator) Advance(now time.Time) {
+	if me.spec.Interval <= 0 {
+		return
+	}
+	if me.open && !now.Before(me.cur.End) {
+		me.CloseCurrent()
+	}
+	if !me.gapFill || !me.closed {
+		return
+	}
+	n := int64(now.Sub(me.last.End) / me.spec.Interval) // number of intervals ended since the last bar
+	skip := n - int64(me.bars.maxSize())
+	if skip < 0 {
+		skip = 0
+	}
+	end := me.last.End.Add(time.Duration(skip) * me.spec.Interval)
+	for i := skip; i < n; i++ {
+		end = end.Add(me.spec.Interval)
+		me.emit(Bar{
+			Start: end.Add(-me.spec.Interval),
+			End:   end,
+			Open:  me.last.Close,
+			High:  me.last.Close,
+			Low:   me.last.Close,
+			Close: me.last.Close,
+		})
+	}
+}
+
+// CloseCurrent completes the in-progress bar early, e.g. at the end of a session.
+// It returns false if there is no bar in progress.
+func (me *BarAggregator) CloseCurrent() bool {
+	if !me.open {
+		return false
+	}
+	me.open = false
+	me.emit(me.cur)
+	return true
+}
+
+func (me *BarAggregator) emit(bar Bar) {
+	me.last = bar
+	me.closed = true
+	me.bars.AddLast(bar)
+	if me.onClose != nil {
+		me.onClose(bar)
+	}
+}
+
+// Current returns the in-progress bar. ok is false if no tick arrived since the last bar closed.
+func (me *BarAggregator) Current() (bar Bar, ok bool) {
+	return me.cur, me.open
+}
+
+// Bars returns the completed bars, from the oldest to the newest.
+func (me *BarAggregator) Bars() *RingBuffer[Bar] {
+	return &me.bars
+}
+
+// Clear drops the completed and in-progress bars.
+func (me *BarAggregator) Clear() {
+	me.bars.Clear()
+	me.open = false
+	me.closed = false
+}
@@ -0,0 +1,221 @@
+package quantainer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func ExampleBarAggregator() {
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	agg := NewBarAggregator(TimeBars(time.Minute), 100)
+	agg.OnClose(func(bar Bar) {
+		fmt.Println(bar.Start.Format("15:04"), bar.Open, bar.High, bar.Low, bar.Close, bar.Volume)
+	})
+	agg.AddAt(100, 1, start.Add(5*time.Second))
+	agg.AddAt(102, 2, start.Add(20*time.Second))
+	agg.AddAt(99, 1, start.Add(50*time.Second))
+	agg.AddAt(101, 3, start.Add(70*time.Second)) // closes the 09:30 bar
+	bar, _ := agg.Current()
+	fmt.Println(bar.Start.Format("15:04"), bar.Ticks)
+	// Output:
+	// 09:30 100 102 99 99 4
+	// 09:31 1
+}
+
+func TestBarAggregator_TimeBars(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	agg := NewBarAggregator(TimeBars(time.Second), 3)
+	agg.SetClock(clock)
+	if _, ok := agg.Current(); ok {
+		t.Fatal("Expected no bar in progress")
+	}
+
+	clock.Advance(200 * time.Millisecond)
+	agg.Add(10, 1)
+	clock.Advance(500 * time.Millisecond)
+	agg.Add(12, 2)
+	bar, ok := agg.Current()
+	if !ok || !bar.Start.Equal(start) || !bar.End.Equal(start.Add(time.Second)) ||
+		bar.Open != 10 || bar.High != 12 || bar.Low != 10 || bar.Close != 12 ||
+		bar.Volume != 3 || bar.Notional != 34 || bar.Ticks != 2 {
+		t.Fatalf("Unexpected bar in progress %+v", bar)
+	}
+
+	// a bar closes once its interval ends, even without a tick
+	agg.Advance(start.Add(999 * time.Millisecond))
+	if agg.Bars().Count() != 0 {
+		t.Fatal("Expected the bar to be still in progress")
+	}
+	clock.Advance(300 * time.Millisecond)
+	agg.Advance(clock.Now())
+	if _, ok := agg.Current(); ok || agg.Bars().Count() != 1 {
+		t.Fatalf("Expected the bar to be closed, got %d bars", agg.Bars().Count())
+	}
+
+	// without gap-filling, empty intervals are skipped
+	agg.AddAt(11, 1, start.Add(3500*time.Millisecond))
+	agg.AddAt(9, 1, start.Add(4*time.Second))
+	bars := agg.Bars().ToSlice()
+	if len(bars) != 2 || !bars[1].Start.Equal(start.Add(3*time.Second)) || bars[1].Close != 11 {
+		t.Fatalf("Unexpected bars %+v", bars)
+	}
+}
+
+func TestBarAggregator_GapFill(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	agg := NewBarAggregator(TimeBars(time.Minute), 10)
+	agg.SetGapFill(true)
+	var closed []Bar
+	agg.OnClose(func(bar Bar) { closed = append(closed, bar) })
+
+	agg.AddAt(100, 1, start)
+	agg.AddAt(105, 1, start.Add(30*time.Second))
+	agg.AddAt(103, 2, start.Add(3*time.Minute+10*time.Second))
+	if len(closed) != 3 {
+		t.Fatalf("Expected a bar and 2 gap-filling bars, got %+v", closed)
+	}
+	for i, bar := range closed[1:] {
+		if !bar.Start.Equal(start.Add(time.Duration(i+1)*time.Minute)) ||
+			bar.Open != 105 || bar.High != 105 || bar.Low != 105 || bar.Close != 105 ||
+			bar.Volume != 0 || bar.Ticks != 0 {
+			t.Fatalf("Unexpected gap-filling bar %+v", bar)
+		}
+	}
+
+	// Advance fills the intervals ended since the last bar closed
+	agg.Advance(start.Add(6 * time.Minute))
+	if len(closed) != 6 || closed[3].Close != 103 || closed[3].Ticks != 1 ||
+		!closed[5].End.Equal(start.Add(6*time.Minute)) || closed[5].Close != 103 {
+		t.Fatalf("Unexpected bars %+v", closed)
+	}
+	if agg.Bars().Count() != 6 {
+		t.Fatalf("Expected 6 bars stored, got %d", agg.Bars().Count())
+	}
+}
+
+func TestBarAggregator_LateTicks(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	at := func(minutes, seconds int) time.Time {
+		return start.Add(time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second)
+	}
+	for _, policy := range []LateTickPolicy{LateTickDrop, LateTickFold} {
+		agg := NewBarAggregator(TimeBars(time.Minute), 10)
+		agg.SetGapFill(true)
+		agg.SetLateTickPolicy(policy)
+		agg.AddAt(100, 1, at(0, 30))
+		agg.AddAt(101, 1, at(2, 30))
+		agg.Advance(at(3, 0))
+		if agg.AddAt(99, 1, at(1, 30)) != (policy == LateTickFold) {
+			t.Fatalf("Policy %d: unexpected result for a late tick", policy)
+		}
+		agg.AddAt(102, 1, at(3, 10))
+		agg.Advance(at(4, 0))
+
+		bars := agg.Bars().ToSlice()
+		if len(bars) != 4 {
+			t.Fatalf("Policy %d: expected 4 bars, got %+v", policy, bars)
+		}
+		for i, bar := range bars {
+			if !bar.Start.Equal(at(i, 0)) {
+				t.Fatalf("Policy %d: bar %d starts at %v", policy, i, bar.Start)
+			}
+		}
+		last := bars[3]
+		switch policy {
+		case LateTickDrop:
+			if last.Ticks != 1 || last.Open != 102 || last.Low != 102 {
+				t.Fatalf("Expected the late tick to be dropped, got %+v", last)
+			}
+		case LateTickFold:
+			if last.Ticks != 2 || last.Open != 99 || last.Low != 99 || last.Close != 102 || last.Volume != 2 {
+				t.Fatalf("Expected the late tick to be folded into the next bar, got %+v", last)
+			}
+		}
+	}
+}
+
+func TestBarAggregator_GapFillCapped(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	agg := NewBarAggregator(TimeBars(time.Minute), 3)
+	agg.SetGapFill(true)
+	closed := 0
+	agg.OnClose(func(Bar) { closed++ })
+	agg.AddAt(100, 1, start)
+	far := start.Add(100 * 365 * 24 * time.Hour)
+	agg.AddAt(101, 1, far)
+	// the bar, then only the gap-filling bars the ring can hold
+	bars := agg.Bars().ToSlice()
+	if closed != 4 || len(bars) != 3 || !bars[2].End.Equal(far) || !bars[0].Start.Equal(far.Add(-3*time.Minute)) {
+		t.Fatalf("Unexpected bars after a far gap: %d closed, %+v", closed, bars)
+	}
+	if bar, _ := agg.Current(); !bar.Start.Equal(far) {
+		t.Fatalf("Unexpected bar in progress %+v", bar)
+	}
+}
+
+func TestBarAggregator_ThresholdBars(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	ticks := NewBarAggregator(TickBars(2), 2)
+	for i := 0; i < 5; i++ {
+		ticks.AddAt(float64(10+i), 1, at(i))
+	}
+	bars := ticks.Bars().ToSlice()
+	if len(bars) != 2 || bars[0].Open != 10 || bars[0].Close != 11 || !bars[0].Start.Equal(at(0)) || !bars[0].End.Equal(at(1)) ||
+		bars[1].Open != 12 || bars[1].Close != 13 {
+		t.Fatalf("Unexpected tick bars %+v", bars)
+	}
+	if bar, ok := ticks.Current(); !ok || bar.Ticks != 1 || bar.Open != 14 {
+		t.Fatalf("Unexpected tick bar in progress %+v", bar)
+	}
+	if !ticks.CloseCurrent() || ticks.CloseCurrent() {
+		t.Fatal("Expected CloseCurrent to close the bar in progress once")
+	}
+	if bars = ticks.Bars().ToSlice(); len(bars) != 2 || bars[1].Open != 14 {
+		t.Fatalf("Expected the oldest bar to be evicted, got %+v", bars)
+	}
+
+	// the tick reaching the threshold is not split
+	volume := NewBarAggregator(VolumeBars(10), 10)
+	volume.AddAt(100, 4, at(0))
+	volume.AddAt(101, 5, at(1))
+	volume.AddAt(102, 3, at(2))
+	volume.AddAt(103, 10, at(3))
+	bars = volume.Bars().ToSlice()
+	if len(bars) != 2 || bars[0].Volume != 12 || bars[0].Ticks != 3 || bars[1].Volume != 10 || bars[1].Open != 103 {
+		t.Fatalf("Unexpected volume bars %+v", bars)
+	}
+
+	dollar := NewBarAggregatorConfigurable(DollarBars(1000), func() int { return 10 })
+	dollar.AddAt(100, 4, at(0))
+	dollar.AddAt(100, 5, at(1))
+	if dollar.Bars().Count() != 0 {
+		t.Fatal("Expected no dollar bar under the threshold")
+	}
+	dollar.AddAt(50, 2, at(2))
+	bars = dollar.Bars().ToSlice()
+	if len(bars) != 1 || bars[0].Notional != 1000 || bars[0].Low != 50 || bars[0].Notional/bars[0].Volume != 1000.0/11 {
+		t.Fatalf("Unexpected dollar bars %+v", bars)
+	}
+
+	dollar.Clear()
+	if _, ok := dollar.Current(); ok || dollar.Bars().Count() != 0 {
+		t.Fatal("Expected no bars after Clear")
+	}
+}
+
+func TestNewBarAggregator_InvalidSpec(t *testing.T) {
+	for _, spec := range []BarSpec{{}, {Ticks: 10, Volume: 5}, TimeBars(-time.Second)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("Expected a panic for %+v", spec)
+				}
+			}()
+			NewBarAggregator(spec, 10)
+		}()
+	}
+}
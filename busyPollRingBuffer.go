@@ -1,89 +1,441 @@
 package quantainer
 
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
 type (
 	// BusyPollRingBuffer is a simple fixed-size, write-only ring buffer designed for busy-polling scenarios.
 	// It should be used where only one goroutine is writing data and one or more goroutines are reading data.
 	// The writer can overwrite old data when the buffer is full.
-	// There is no synchronization between the readers and the writer. Readers would miss data if they are too slow.
+	// The writer never waits for the readers. Readers would miss data if they are too slow,
+	// which they can detect with ReadSeq.
+	// Every written item gets a sequence number, starting from 0. The slot of the next write is reserved for
+	// the writer, so readers can see at most Len()-1 items.
+	//
+	// Writes are published with atomic sequence numbers, and every slot carries a version stamp (a seqlock),
+	// so readers either get a fully written item or detect that it was overwritten while being read.
+	// If T contains no pointers and its size is a multiple of 4 bytes, items are copied in and out of the slots
+	// with atomic word loads and stores, which are ordered with the stamps on every CPU, so the writer never
+	// waits. Other items are copied under a per-slot read-write lock, which readers share and only try to take,
+	// so readers never block each other, and the writer may wait for a reader copying the item it is about
+	// to overwrite, but never for a slow reader.
 	BusyPollRingBuffer[T any] struct {
-		buf      []T
-		from, to int
+		busyPollRing[T]
+		to      int // slot of the next write, only accessed by the writer
+		pending T   // the item between BeginWrite and EndWrite
+	}
+
+	// busyPollRing is the storage shared by the single and multi-producer ring buffers and their readers.
+	busyPollRing[T any] struct {
+		*busyPollHeader
+		data     []T
+		versions []atomic.Uint64 // version stamps of data, 2*seq+1 while item seq is being written, 2*seq+2 once it is written
+		word     uintptr         // 8 or 4 if data is copied with atomic loads and stores of that many bytes, 0 if under locks
+		locks    []sync.RWMutex  // guard data if word is 0
+
+		remote  bool         // attached to the buffer of another process, whose writer cannot wake parked readers
+		waiters atomic.Int32 // number of readers parked in ReadWait
+		mu      sync.Mutex
+		notify  chan struct{} // closed to wake up parked readers
+
+		statsMu sync.Mutex
+		readers []*busyPollReaderStats // registered readers
+	}
+
+	// busyPollHeader holds the sequence numbers, which may live in shared memory along with data and versions.
+	busyPollHeader struct {
+		seq  atomic.Uint64 // sequence number of the next write, i.e. the total number of items written (or claimed)
+		from atomic.Uint64 // sequence number of the first item of the last write
 	}
 
 	BusyPollRingBufferReader[T any] struct {
-		buf     *BusyPollRingBuffer[T]
-		current int
+		buf     *busyPollRing[T]
+		current int    // slot of the next read
+		next    uint64 // sequence number of the next read
+		wait    WaitStrategy
+		stats   *busyPollReaderStats // nil unless the reader is registered
 	}
 )
 
 func NewBusyPollRingBuffer[T any](size int) *BusyPollRingBuffer[T] {
-	return &BusyPollRingBuffer[T]{
-		buf: make([]T, size),
+	result := &BusyPollRingBuffer[T]{}
+	result.init(size)
+	return result
+}
+
+func (me *busyPollRing[T]) init(size int) {
+	me.busyPollHeader = &busyPollHeader{}
+	me.data = make([]T, size)
+	me.versions = make([]atomic.Uint64, size)
+	me.initCopy()
+}
+
+// initCopy decides how items are copied in and out of data.
+// Words must be aligned to their size, which holds if it divides the size of T, as allocations are aligned
+// to the largest power of 2 up to 8 dividing their size, and shared memory data starts at a 64 byte boundary.
+func (me *busyPollRing[T]) initCopy() {
+	me.word = atomicWord[T]()
+	if me.word == 0 {
+		me.locks = make([]sync.RWMutex, len(me.data))
+	}
+}
+
+// atomicWord returns the size of the words items of type T are copied with, or 0 if they must be copied
+// under locks because they contain pointers, which the garbage collector must see being written,
+// or their size is not a multiple of 4.
+func atomicWord[T any]() uintptr {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	switch size := t.Size(); {
+	case !pointerFree(t):
+		return 0
+	case size%8 == 0:
+		return 8
+	case size%4 == 0:
+		return 4
 	}
+	return 0
 }
 
-func (me *BusyPollRingBuffer[T]) Len() int {
-	return len(me.buf)
+// checkRawType returns an error if T cannot be stored as raw memory, in a file or shared between processes.
+func checkRawType[T any]() error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Size() == 0 {
+		return fmt.Errorf("quantainer: %v has size 0 and cannot be stored as raw memory", t)
+	}
+	if !pointerFree(t) {
+		return fmt.Errorf("quantainer: %v contains pointers and cannot be stored as raw memory", t)
+	}
+	return nil
 }
 
-func (me *BusyPollRingBuffer[T]) Write(v T) {
-	me.buf[me.to] = v
-	me.from = me.to
+func pointerFree(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return t.Len() == 0 || pointerFree(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !pointerFree(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// store copies v into slot i. The caller must hold the slot lock if there is one.
+func (me *busyPollRing[T]) store(i int, v *T) {
+	dst, src := unsafe.Pointer(&me.data[i]), unsafe.Pointer(v)
+	switch me.word {
+	case 8:
+		n := unsafe.Sizeof(*v) / 8
+		d, s := unsafe.Slice((*uint64)(dst), n), unsafe.Slice((*uint64)(src), n)
+		for k := range s {
+			atomic.StoreUint64(&d[k], s[k])
+		}
+	case 4:
+		n := unsafe.Sizeof(*v) / 4
+		d, s := unsafe.Slice((*uint32)(dst), n), unsafe.Slice((*uint32)(src), n)
+		for k := range s {
+			atomic.StoreUint32(&d[k], s[k])
+		}
+	default:
+		me.data[i] = *v
+	}
+}
+
+// load copies slot i into v. The caller must hold the slot lock if there is one.
+func (me *busyPollRing[T]) load(i int, v *T) {
+	dst, src := unsafe.Pointer(v), unsafe.Pointer(&me.data[i])
+	switch me.word {
+	case 8:
+		n := unsafe.Sizeof(*v) / 8
+		d, s := unsafe.Slice((*uint64)(dst), n), unsafe.Slice((*uint64)(src), n)
+		for k := range s {
+			d[k] = atomic.LoadUint64(&s[k])
+		}
+	case 4:
+		n := unsafe.Sizeof(*v) / 4
+		d, s := unsafe.Slice((*uint32)(dst), n), unsafe.Slice((*uint32)(src), n)
+		for k := range s {
+			d[k] = atomic.LoadUint32(&s[k])
+		}
+	default:
+		*v = me.data[i]
+	}
+}
+
+// writeSlot stores v as item seq in slot i, between the version stamps that tell readers it is being written.
+func (me *busyPollRing[T]) writeSlot(i int, seq uint64, v *T) {
+	if me.word == 0 {
+		me.locks[i].Lock()
+		defer me.locks[i].Unlock()
+	}
+	me.versions[i].Store(2*seq + 1)
+	me.store(i, v)
+	me.versions[i].Store(2*seq + 2)
+}
+
+// readSlot copies item seq from slot i into v. It returns false if the slot does not hold item seq,
+// or it was overwritten while being copied, in which case v may be torn and must not be used.
+func (me *busyPollRing[T]) readSlot(i int, seq uint64, v *T) bool {
+	want := 2*seq + 2
+	if me.versions[i].Load() != want {
+		return false
+	}
+	if me.word == 0 {
+		// fails only while the writer holds or waits for the lock, i.e. the item is being overwritten
+		if !me.locks[i].TryRLock() {
+			return false
+		}
+		defer me.locks[i].RUnlock()
+	}
+	me.load(i, v)
+	return me.versions[i].Load() == want
+}
+
+func (me *busyPollRing[T]) Len() int {
+	return len(me.data)
+}
+
+// Seq returns the sequence number of the next write, which is also the total number of items written.
+func (me *busyPollRing[T]) Seq() uint64 {
+	return me.seq.Load()
+}
+
+// oldest returns the sequence number of the oldest item readers can still read, given the next write seq.
+func (me *busyPollRing[T]) oldest(seq uint64) uint64 {
+	window := uint64(len(me.data) - 1)
+	if seq <= window {
+		return 0
+	}
+	return seq - window
+}
+
+func (me *busyPollRing[T]) slot(seq uint64) int {
+	if len(me.data) == 0 {
+		return 0
+	}
+	return int(seq % uint64(len(me.data)))
+}
+
+// publish makes item seq visible to the readers and moves to the next slot.
+func (me *BusyPollRingBuffer[T]) publish(seq uint64) {
+	me.seq.Store(seq + 1)
+	if me.waiters.Load() != 0 {
+		me.wake()
+	}
 	me.to++
-	if me.to >= len(me.buf) {
+	if me.to >= len(me.data) {
 		me.to = 0
 	}
 }
 
+func (me *BusyPollRingBuffer[T]) write(seq uint64, v T) {
+	me.writeSlot(me.to, seq, &v)
+	me.publish(seq)
+}
+
+func (me *BusyPollRingBuffer[T]) Write(v T) {
+	seq := me.seq.Load()
+	me.from.Store(seq)
+	me.write(seq, v)
+}
+
+// BeginWrite returns a pointer to the next item so it can be written field by field.
+// It points to a private copy, which still holds the previous item and is copied into the next slot by EndWrite,
+// as readers must not see the slot being written with plain stores.
+// Readers do not see the item until EndWrite is called.
 func (me *BusyPollRingBuffer[T]) BeginWrite() *T {
-	ptr := &me.buf[me.to]
-	return ptr
+	return &me.pending
 }
 
 func (me *BusyPollRingBuffer[T]) EndWrite() {
-	to := me.to
-	me.from = to
-	to++
-	if to >= len(me.buf) {
-		to = 0
-	}
-	me.to = to
+	seq := me.seq.Load()
+	me.writeSlot(me.to, seq, &me.pending)
+	me.from.Store(seq)
+	me.publish(seq)
 }
 
 func (me *BusyPollRingBuffer[T]) WriteMany(vs []T) {
-	from := me.to
-	to := from
-	size := len(me.buf)
+	seq := me.seq.Load()
+	me.from.Store(seq)
 	for _, v := range vs {
-		me.buf[to] = v
-		to++
-		if to >= size {
-			to = 0
-		}
+		me.write(seq, v)
+		seq++
 	}
-	me.to = to
-	me.from = from
 }
 
-// Reader creates a new reader for the ring buffer.
-// There is no synchronization between the writer and the reader.
+// Reader creates a new reader for the ring buffer, starting from the last write.
+// The writer does not wait for the readers.
 // Usually the reader would busy poll the buffer, or spin-wait until new data is available.
-func (me *BusyPollRingBuffer[T]) Reader() *BusyPollRingBufferReader[T] {
-	return &BusyPollRingBufferReader[T]{
-		buf:     me,
-		current: me.from,
+func (me *busyPollRing[T]) Reader() *BusyPollRingBufferReader[T] {
+	result := &BusyPollRingBufferReader[T]{
+		buf: me,
+	}
+	next := me.from.Load()
+	if oldest := me.oldest(me.seq.Load()); next < oldest {
+		next = oldest
+	}
+	result.seek(next)
+	return result
+}
+
+func (me *BusyPollRingBufferReader[T]) seek(seq uint64) {
+	me.next = seq
+	me.current = me.buf.slot(seq)
+	if me.stats != nil {
+		me.stats.next.Store(seq)
+	}
+}
+
+// skip moves a lapped reader to the oldest item still available.
+func (me *BusyPollRingBufferReader[T]) skip(oldest uint64) {
+	if me.stats != nil {
+		me.stats.overruns.Add(1)
+		me.stats.lost.Add(oldest - me.next)
 	}
+	me.seek(oldest)
 }
 
+// Read reads the next item into result. It returns false if no new item is available.
+// If the writer has overwritten items the reader has not read yet, they are skipped silently.
+// Use ReadSeq to detect that.
 func (me *BusyPollRingBufferReader[T]) Read(result *T) (ok bool) {
-	if me.current == me.buf.to {
+	_, lost, ok := me.ReadSeq(result)
+	if lost != 0 {
+		_, _, ok = me.ReadSeq(result)
+	}
+	return
+}
+
+// ReadSeq reads the next item into result and returns its sequence number.
+// ok is false if no item was read. In that case lost is non-zero if the writer has overwritten
+// items the reader has not read yet: it is the number of items skipped, and the next read
+// continues from the oldest item still available.
+func (me *BusyPollRingBufferReader[T]) ReadSeq(result *T) (seq, lost uint64, ok bool) {
+	buf := me.buf
+	next := me.next
+	head := buf.seq.Load()
+	if next >= head {
 		return
 	}
-	*result = me.buf.buf[me.current]
-	ok = true
-	me.current++
-	if me.current >= len(me.buf.buf) {
-		me.current = 0
+	if me.stats != nil {
+		me.stats.observe(head - next)
+	}
+	if next >= buf.oldest(head) {
+		i := me.current
+		want := 2*next + 2
+		version := buf.versions[i].Load()
+		if version < want {
+			return // claimed by a producer but not committed yet
+		}
+		var v T
+		if ok = version == want && buf.readSlot(i, next, &v); ok {
+			*result = v
+		}
 	}
+	if !ok {
+		// the reader has been lapped, possibly while reading
+		if oldest := buf.oldest(buf.seq.Load()); next < oldest {
+			lost = oldest - next
+			me.skip(oldest)
+		}
+		return
+	}
+	seq = next
+	me.advance(1)
 	return
 }
+
+func (me *BusyPollRingBufferReader[T]) advance(n int) {
+	me.next += uint64(n)
+	me.current += n
+	if me.current >= len(me.buf.data) {
+		me.current -= len(me.buf.data)
+	}
+	if me.stats != nil {
+		me.stats.next.Store(me.next)
+	}
+}
+
+// available skips lost items and returns how many of the following items (at most max) are committed.
+func (me *BusyPollRingBufferReader[T]) available(max int) (n int) {
+	buf := me.buf
+	head := buf.seq.Load()
+	if me.next >= head {
+		return
+	}
+	if me.stats != nil {
+		me.stats.observe(head - me.next)
+	}
+	if oldest := buf.oldest(head); me.next < oldest {
+		me.skip(oldest)
+	}
+	if pending := head - me.next; pending < uint64(max) {
+		max = int(pending)
+	}
+	i := me.current
+	for want := 2*me.next + 2; n < max && buf.versions[i].Load() == want; want += 2 {
+		n++
+		i++
+		if i >= len(buf.data) {
+			i = 0
+		}
+	}
+	return
+}
+
+// copyOut copies len(dst) items starting at the reader's position into dst.
+// It returns false if it detects that an item has been overwritten.
+func (me *BusyPollRingBufferReader[T]) copyOut(dst []T) bool {
+	buf := me.buf
+	i, seq := me.current, me.next
+	for k := range dst {
+		if !buf.readSlot(i, seq, &dst[k]) {
+			return false
+		}
+		seq++
+		if i++; i >= len(buf.data) {
+			i = 0
+		}
+	}
+	return true
+}
+
+// intact returns true if the items from seq on have not been overwritten.
+func (me *busyPollRing[T]) intact(seq uint64) bool {
+	return seq >= me.oldest(me.seq.Load())
+}
+
+// ReadMany reads up to len(dst) items into dst and returns how many were read.
+// Like Read, it silently skips items overwritten before they could be read.
+// Elements of dst after the first n may be overwritten with torn data and must not be used.
+// There is no zero-copy view of the buffer to read bursts in place: the writer may overwrite any slot
+// at any time, so items are only safe to use once copied out and checked against their version stamps.
+func (me *BusyPollRingBufferReader[T]) ReadMany(dst []T) (n int) {
+	for {
+		n = me.available(len(dst))
+		if n == 0 {
+			return
+		}
+		if me.copyOut(dst[:n]) && me.buf.intact(me.next) {
+			me.advance(n)
+			return
+		}
+	}
+}
+
+// Next returns the sequence number of the next item the reader will read.
+func (me *BusyPollRingBufferReader[T]) Next() uint64 {
+	return me.next
+}
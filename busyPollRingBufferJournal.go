@@ -0,0 +1,422 @@
+package quantainer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+var (
+	// ErrJournalCorrupt is returned when a journal has a bad header or a record fails its checksum.
+	ErrJournalCorrupt = errors.New("quantainer: corrupt journal")
+	// errJournalData is wrapped with ErrJournalCorrupt when a record's header is intact but its data is not.
+	errJournalData = errors.New("data checksum mismatch")
+)
+
+const (
+	journalMagic         = "QTNTJRNL"
+	journalVersion       = 1
+	journalHeaderSize    = 16 // magic, version, reserved
+	journalRecordHeader  = 28 // length, header checksum, data checksum, seq, time
+	journalMaxRecordSize = 1 << 30
+	journalBatch         = 64
+)
+
+var journalTable = crc32.MakeTable(crc32.Castagnoli)
+
+type (
+	// JournalCodec encodes and decodes the items of a journal.
+	JournalCodec[T any] interface {
+		// Append appends the encoding of v to dst and returns the extended slice.
+		Append(dst []byte, v *T) ([]byte, error)
+		Decode(data []byte, v *T) error
+	}
+
+	// RawCodec stores fixed-size items without pointers, strings, slices, maps, interfaces, channels or funcs
+	// by copying their memory. Journals written with it can only be read back on machines with the same
+	// byte order and with the same definition of T.
+	RawCodec[T any] struct{}
+
+	// GobCodec stores items with encoding/gob. It works for any T gob can encode, but every record
+	// carries its own type information, so it is much bigger and slower than RawCodec.
+	GobCodec[T any] struct{}
+
+	// JournalWriter tails a BusyPollRingBuffer through its own reader and appends every item it reads
+	// to a journal as a length-prefixed, checksummed record with the item's sequence number and time.
+	// By default the time is when the journal encoded the item, which lags the write into the buffer
+	// while the journal catches up; use SetTimeFunc to record a time carried by the item itself.
+	// Items the writer overwrote before the journal could read them show up as gaps in the sequence
+	// numbers and are counted by Lost.
+	JournalWriter[T any] struct {
+		reader *BusyPollRingBufferReader[T]
+		codec  JournalCodec[T]
+		w      *bufio.Writer
+		closer io.Closer
+		next   uint64 // sequence number of the next item expected from the reader
+		lost   atomic.Uint64
+		timeOf func(v *T) time.Time
+		batch  []T
+		record []byte
+	}
+
+	// JournalRecord is an item read back from a journal.
+	JournalRecord[T any] struct {
+		Seq   uint64
+		Time  time.Time
+		Value T
+	}
+
+	// JournalReader reads back the records of a journal in the order they were written.
+	JournalReader[T any] struct {
+		r      *bufio.Reader
+		codec  JournalCodec[T]
+		header [journalRecordHeader]byte
+		data   []byte
+	}
+)
+
+// NewRawCodec returns a RawCodec, or an error if T cannot be stored as raw memory.
+func NewRawCodec[T any]() (result RawCodec[T], err error) {
+	err = checkRawType[T]()
+	return
+}
+
+func (me RawCodec[T]) Append(dst []byte, v *T) ([]byte, error) {
+	return append(dst, unsafe.Slice((*byte)(unsafe.Pointer(v)), unsafe.Sizeof(*v))...), nil
+}
+
+func (me RawCodec[T]) Decode(data []byte, v *T) error {
+	if uintptr(len(data)) != unsafe.Sizeof(*v) {
+		return fmt.Errorf("%w: record of %d bytes, want %d", ErrJournalCorrupt, len(data), unsafe.Sizeof(*v))
+	}
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(v)), len(data)), data)
+	return nil
+}
+
+func (me GobCodec[T]) Append(dst []byte, v *T) ([]byte, error) {
+	buf := bytes.NewBuffer(dst)
+	err := gob.NewEncoder(buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+func (me GobCodec[T]) Decode(data []byte, v *T) error {
+	*v = *new(T) // gob leaves fields with zero values untouched
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// NewJournalWriter starts a new journal on w. reader must be dedicated to the journal, usually created
+// with Reader() of the buffer just before; its WaitStrategy decides how Run waits for new items.
+func NewJournalWriter[T any](w io.Writer, reader *BusyPollRingBufferReader[T], codec JournalCodec[T]) (result *JournalWriter[T], err error) {
+	result = newJournalWriter(w, reader, codec)
+	if err = writeJournalHeader(result.w); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// OpenJournalFile opens the journal at path for appending, creating it if it does not exist.
+// An incomplete or corrupt final record, as left by a crash in the middle of a write, is truncated
+// so the new records can be read back: less than a record header, or an intact header with its data
+// short or failing its checksum. Any other corruption, such as a bad header or a corrupt record followed
+// by more data, is not truncated and ErrJournalCorrupt is returned instead. Close closes the file.
+func OpenJournalFile[T any](path string, reader *BusyPollRingBufferReader[T], codec JournalCodec[T]) (result *JournalWriter[T], err error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return
+	}
+	result = newJournalWriter(f, reader, codec)
+	result.closer = f
+	if info, e := f.Stat(); e != nil {
+		err = e
+	} else if info.Size() == 0 {
+		err = writeJournalHeader(result.w)
+	} else {
+		err = truncateJournal(f, info.Size())
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return
+}
+
+// truncateJournal checks the journal in f, truncates an incomplete or corrupt final record
+// and moves the file offset to the end.
+func truncateJournal(f *os.File, size int64) error {
+	r := &JournalReader[struct{}]{r: bufio.NewReaderSize(f, 64*1024)}
+	if err := readJournalHeader(r.r); err != nil {
+		return err
+	}
+	valid := int64(journalHeaderSize)
+	for {
+		data, err := r.read()
+		if err != nil {
+			// A crash can only tear the last record: it leaves either less than a header, or an intact header
+			// with its data short or corrupt. Anything else is corruption that truncating would hide.
+			if errors.Is(err, errJournalData) {
+				if end := valid + journalRecordHeader + int64(binary.LittleEndian.Uint32(r.header[0:])); end < size {
+					return fmt.Errorf("%w at offset %d of %d bytes", err, valid, size)
+				}
+			} else if errors.Is(err, ErrJournalCorrupt) {
+				return fmt.Errorf("%w at offset %d of %d bytes", err, valid, size)
+			} else if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+				return err
+			}
+			break
+		}
+		valid += int64(journalRecordHeader + len(data))
+	}
+	if valid != size {
+		if err := f.Truncate(valid); err != nil {
+			return err
+		}
+	}
+	_, err := f.Seek(valid, io.SeekStart)
+	return err
+}
+
+func newJournalWriter[T any](w io.Writer, reader *BusyPollRingBufferReader[T], codec JournalCodec[T]) *JournalWriter[T] {
+	return &JournalWriter[T]{
+		reader: reader,
+		codec:  codec,
+		w:      bufio.NewWriterSize(w, 64*1024),
+		next:   reader.Next(),
+		batch:  make([]T, journalBatch),
+	}
+}
+
+func writeJournalHeader(w io.Writer) error {
+	var header [journalHeaderSize]byte
+	copy(header[:], journalMagic)
+	binary.LittleEndian.PutUint32(header[8:], journalVersion)
+	_, err := w.Write(header[:])
+	return err
+}
+
+func readJournalHeader(r io.Reader) error {
+	var header [journalHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return fmt.Errorf("%w: missing header", ErrJournalCorrupt)
+		}
+		return err
+	}
+	if string(header[:8]) != journalMagic {
+		return fmt.Errorf("%w: not a journal", ErrJournalCorrupt)
+	}
+	if version := binary.LittleEndian.Uint32(header[8:]); version != journalVersion {
+		return fmt.Errorf("%w: version %d, want %d", ErrJournalCorrupt, version, journalVersion)
+	}
+	return nil
+}
+
+// SetTimeFunc sets the function returning the time journaled with an item, such as the exchange timestamp
+// of a tick, so records are timed as the items were produced. nil restores the default of time.Now.
+func (me *JournalWriter[T]) SetTimeFunc(f func(v *T) time.Time) {
+	me.timeOf = f
+}
+
+func (me *JournalWriter[T]) timestamp(v *T) int64 {
+	if me.timeOf != nil {
+		return me.timeOf(v).UnixNano()
+	}
+	return time.Now().UnixNano()
+}
+
+// Poll journals all items available in the buffer and returns how many were written.
+// Records are buffered; call Flush to write them out.
+func (me *JournalWriter[T]) Poll() (n int, err error) {
+	for {
+		k := me.reader.ReadMany(me.batch)
+		if k == 0 {
+			return
+		}
+		seq := me.reader.Next() - uint64(k)
+		if seq != me.next {
+			me.lost.Add(seq - me.next)
+		}
+		for i := range me.batch[:k] {
+			if err = me.write(seq+uint64(i), me.timestamp(&me.batch[i]), &me.batch[i]); err != nil {
+				return
+			}
+		}
+		me.next = seq + uint64(k)
+		n += k
+	}
+}
+
+func (me *JournalWriter[T]) write(seq uint64, now int64, v *T) (err error) {
+	record := append(me.record[:0], make([]byte, journalRecordHeader)...)
+	if record, err = me.codec.Append(record, v); err != nil {
+		return
+	}
+	me.record = record
+	length := len(record) - journalRecordHeader
+	if length > journalMaxRecordSize {
+		return fmt.Errorf("quantainer: journal record of %d bytes is too big", length)
+	}
+	binary.LittleEndian.PutUint32(record[0:], uint32(length))
+	binary.LittleEndian.PutUint32(record[8:], crc32.Checksum(record[journalRecordHeader:], journalTable))
+	binary.LittleEndian.PutUint64(record[12:], seq)
+	binary.LittleEndian.PutUint64(record[20:], uint64(now))
+	binary.LittleEndian.PutUint32(record[4:], journalHeaderSum(record))
+	_, err = me.w.Write(record)
+	return
+}
+
+// Run journals items as they arrive until ctx is done, flushing whenever it catches up with the buffer.
+// It returns ctx.Err() once everything read has been flushed, or the first error writing the journal.
+func (me *JournalWriter[T]) Run(ctx context.Context) error {
+	for {
+		n, err := me.Poll()
+		if err != nil {
+			return err
+		}
+		if n != 0 {
+			continue
+		}
+		if err = me.Flush(); err != nil {
+			return err
+		}
+		if err = me.reader.ReadWait(ctx, &me.batch[0]); err != nil {
+			if errors.Is(err, ErrWaitTimeout) {
+				continue
+			}
+			return err
+		}
+		seq := me.reader.Next() - 1
+		if seq != me.next {
+			me.lost.Add(seq - me.next)
+		}
+		if err = me.write(seq, me.timestamp(&me.batch[0]), &me.batch[0]); err != nil {
+			return err
+		}
+		me.next = seq + 1
+	}
+}
+
+// Lost returns the number of items overwritten before the journal could read them.
+func (me *JournalWriter[T]) Lost() uint64 {
+	return me.lost.Load()
+}
+
+// Flush writes out the buffered records.
+func (me *JournalWriter[T]) Flush() error {
+	return me.w.Flush()
+}
+
+// Close flushes the journal, and closes its file if it was opened with OpenJournalFile.
+func (me *JournalWriter[T]) Close() error {
+	err := me.Flush()
+	if me.closer != nil {
+		if e := me.closer.Close(); err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// NewJournalReader checks the journal header and returns a reader of the records that follow.
+func NewJournalReader[T any](r io.Reader, codec JournalCodec[T]) (*JournalReader[T], error) {
+	result := &JournalReader[T]{
+		r:     bufio.NewReaderSize(r, 64*1024),
+		codec: codec,
+	}
+	if err := readJournalHeader(result.r); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Next reads the next record into rec. It returns io.EOF at the end of the journal, io.ErrUnexpectedEOF
+// if the journal ends in the middle of a record, as it does when the process writing it crashed,
+// and an error wrapping ErrJournalCorrupt if the record fails its checksum.
+func (me *JournalReader[T]) Next(rec *JournalRecord[T]) error {
+	data, err := me.read()
+	if err != nil {
+		return err
+	}
+	rec.Seq = binary.LittleEndian.Uint64(me.header[12:])
+	rec.Time = time.Unix(0, int64(binary.LittleEndian.Uint64(me.header[20:])))
+	return me.codec.Decode(data, &rec.Value)
+}
+
+// journalHeaderSum returns the checksum of a record header, which covers all of it but the checksum itself,
+// so the length can be trusted before the data is read.
+func journalHeaderSum(header []byte) uint32 {
+	return crc32.Update(crc32.Checksum(header[0:4], journalTable), journalTable, header[8:journalRecordHeader])
+}
+
+// read reads the next record into me.header and returns its checked data.
+// Only io.ErrUnexpectedEOF means the journal ends in the middle of a record whose header is not corrupt.
+func (me *JournalReader[T]) read() ([]byte, error) {
+	if _, err := io.ReadFull(me.r, me.header[:]); err != nil {
+		return nil, err
+	}
+	if journalHeaderSum(me.header[:]) != binary.LittleEndian.Uint32(me.header[4:]) {
+		return nil, fmt.Errorf("%w: header checksum mismatch", ErrJournalCorrupt)
+	}
+	length := binary.LittleEndian.Uint32(me.header[0:])
+	if length > journalMaxRecordSize {
+		return nil, fmt.Errorf("%w: record of %d bytes", ErrJournalCorrupt, length)
+	}
+	if cap(me.data) < int(length) {
+		me.data = make([]byte, length)
+	}
+	data := me.data[:length]
+	if _, err := io.ReadFull(me.r, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if crc32.Checksum(data, journalTable) != binary.LittleEndian.Uint32(me.header[8:]) {
+		return nil, fmt.Errorf("%w: %w", ErrJournalCorrupt, errJournalData)
+	}
+	return data, nil
+}
+
+// ReplayJournal writes the records of r into buf and returns how many were replayed.
+// With a speed of 0 it replays as fast as possible; otherwise it keeps the time between records as journaled,
+// divided by speed, so 1 replays at the original pacing and 2 twice as fast.
+// It returns nil at the end of the journal, and the error of Next or ctx otherwise.
+// buf numbers the items from its own sequence on, so gaps in the journal are not reproduced.
+func ReplayJournal[T any](ctx context.Context, r *JournalReader[T], buf *BusyPollRingBuffer[T], speed float64) (n int, err error) {
+	var rec JournalRecord[T]
+	var first time.Time
+	var start time.Time
+	for ; ; n++ {
+		if err = r.Next(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			return
+		}
+		if speed > 0 {
+			if n == 0 {
+				first, start = rec.Time, time.Now()
+			} else if wait := time.Until(start.Add(time.Duration(float64(rec.Time.Sub(first)) / speed))); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return n, ctx.Err()
+				}
+			}
+		} else if err = ctx.Err(); err != nil {
+			return
+		}
+		buf.Write(rec.Value)
+	}
+}
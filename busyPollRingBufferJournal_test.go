@@ -0,0 +1,358 @@
+package quantainer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type journalTick struct {
+	N     int64
+	Price float64
+}
+
+func readJournal[T any](t *testing.T, data []byte, codec JournalCodec[T]) (records []JournalRecord[T], err error) {
+	t.Helper()
+	r, err := NewJournalReader[T](bytes.NewReader(data), codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		var rec JournalRecord[T]
+		if err = r.Next(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			return
+		}
+		records = append(records, rec)
+	}
+}
+
+func TestJournalWriter_RoundTrip(t *testing.T) {
+	buf := NewBusyPollRingBuffer[journalTick](16)
+	codec, err := NewRawCodec[journalTick]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	journal, err := NewJournalWriter[journalTick](&out, buf.Reader(), codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now()
+	for i := 0; i < 10; i++ {
+		buf.Write(journalTick{N: int64(i), Price: float64(i) / 4})
+	}
+	if n, err := journal.Poll(); err != nil || n != 10 {
+		t.Fatalf("Poll want 10 got %d (err=%v)", n, err)
+	}
+	if err := journal.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := readJournal[journalTick](t, out.Bytes(), codec)
+	if err != nil || len(records) != 10 {
+		t.Fatalf("Expected 10 records, got %d (err=%v)", len(records), err)
+	}
+	for i, rec := range records {
+		if rec.Seq != uint64(i) || rec.Value.N != int64(i) || rec.Value.Price != float64(i)/4 || rec.Time.Before(before) {
+			t.Fatalf("record %d: %+v", i, rec)
+		}
+	}
+
+	r, _ := NewJournalReader[journalTick](bytes.NewReader(out.Bytes()), codec)
+	replayed := NewBusyPollRingBuffer[journalTick](16)
+	reader := replayed.Reader()
+	if n, err := ReplayJournal(context.Background(), r, replayed, 0); err != nil || n != 10 {
+		t.Fatalf("ReplayJournal want 10 got %d (err=%v)", n, err)
+	}
+	var val journalTick
+	for i := 0; i < 10; i++ {
+		if !reader.Read(&val) || val.N != int64(i) {
+			t.Fatalf("Expected item %d, got %+v", i, val)
+		}
+	}
+}
+
+func TestJournalWriter_Lost(t *testing.T) {
+	buf := NewBusyPollRingBuffer[int](4)
+	var out bytes.Buffer
+	journal, _ := NewJournalWriter[int](&out, buf.Reader(), RawCodec[int]{})
+
+	buf.Write(0)
+	journal.Poll()
+	for i := 1; i < 10; i++ {
+		buf.Write(i)
+	}
+	journal.Poll()
+	journal.Flush()
+	if journal.Lost() != 6 {
+		t.Fatalf("Lost want 6 got %d", journal.Lost())
+	}
+
+	records, err := readJournal[int](t, out.Bytes(), RawCodec[int]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seqs []uint64
+	for _, rec := range records {
+		if uint64(rec.Value) != rec.Seq {
+			t.Fatalf("record %+v", rec)
+		}
+		seqs = append(seqs, rec.Seq)
+	}
+	if !reflect.DeepEqual(seqs, []uint64{0, 7, 8, 9}) {
+		t.Fatalf("seqs want [0 7 8 9] got %v", seqs)
+	}
+}
+
+func TestJournalReader_Corrupt(t *testing.T) {
+	buf := NewBusyPollRingBuffer[int](8)
+	var out bytes.Buffer
+	journal, _ := NewJournalWriter[int](&out, buf.Reader(), RawCodec[int]{})
+	buf.WriteMany([]int{1, 2, 3})
+	journal.Poll()
+	journal.Close()
+	data := out.Bytes()
+
+	// a crash in the middle of the last record
+	records, err := readJournal[int](t, data[:len(data)-1], RawCodec[int]{})
+	if !errors.Is(err, io.ErrUnexpectedEOF) || len(records) != 2 {
+		t.Fatalf("Expected 2 records and io.ErrUnexpectedEOF, got %d (err=%v)", len(records), err)
+	}
+
+	corrupt := append([]byte(nil), data...)
+	corrupt[len(corrupt)-1] ^= 1
+	records, err = readJournal[int](t, corrupt, RawCodec[int]{})
+	if !errors.Is(err, ErrJournalCorrupt) || len(records) != 2 {
+		t.Fatalf("Expected 2 records and ErrJournalCorrupt, got %d (err=%v)", len(records), err)
+	}
+
+	if _, err := NewJournalReader[int](bytes.NewReader([]byte("not a journal at all")), RawCodec[int]{}); !errors.Is(err, ErrJournalCorrupt) {
+		t.Fatalf("Expected ErrJournalCorrupt for a bad header, got %v", err)
+	}
+	if _, err := NewRawCodec[string](); err == nil {
+		t.Fatal("Expected RawCodec to reject strings")
+	}
+}
+
+func TestJournalWriter_GobCodec(t *testing.T) {
+	type order struct {
+		Symbol string
+		Qty    int
+	}
+	buf := NewBusyPollRingBuffer[order](8)
+	var out bytes.Buffer
+	journal, _ := NewJournalWriter[order](&out, buf.Reader(), GobCodec[order]{})
+	buf.WriteMany([]order{{"BTC", 1}, {"ETH", 0}})
+	journal.Poll()
+	journal.Close()
+
+	records, err := readJournal[order](t, out.Bytes(), GobCodec[order]{})
+	if err != nil || len(records) != 2 || records[0].Value != (order{"BTC", 1}) || records[1].Value != (order{"ETH", 0}) {
+		t.Fatalf("Unexpected records %+v (err=%v)", records, err)
+	}
+}
+
+func TestOpenJournalFile_Append(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	buf := NewBusyPollRingBuffer[int](8)
+	reader := buf.Reader()
+	for _, vs := range [][]int{{1, 2}, {3}} {
+		journal, err := OpenJournalFile[int](path, reader, RawCodec[int]{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.WriteMany(vs)
+		journal.Poll()
+		if err := journal.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := NewJournalReader[int](f, RawCodec[int]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values []int
+	var rec JournalRecord[int]
+	for r.Next(&rec) == nil {
+		values = append(values, rec.Value)
+	}
+	if !reflect.DeepEqual(values, []int{1, 2, 3}) {
+		t.Fatalf("values want [1 2 3] got %v", values)
+	}
+}
+
+func TestOpenJournalFile_TruncatesTornTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	buf := NewBusyPollRingBuffer[int](8)
+	reader := buf.Reader()
+	journal, err := OpenJournalFile[int](path, reader, RawCodec[int]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.WriteMany([]int{1, 2})
+	journal.Poll()
+	journal.Close()
+	info, _ := os.Stat(path)
+	if err := os.Truncate(path, info.Size()-3); err != nil { // crash in the middle of the second record
+		t.Fatal(err)
+	}
+
+	if journal, err = OpenJournalFile[int](path, reader, RawCodec[int]{}); err != nil {
+		t.Fatal(err)
+	}
+	buf.Write(3)
+	journal.Poll()
+	journal.Close()
+
+	data, _ := os.ReadFile(path)
+	records, err := readJournal[int](t, data, RawCodec[int]{})
+	if err != nil || len(records) != 2 || records[0].Value != 1 || records[1].Value != 3 || records[1].Seq != 2 {
+		t.Fatalf("Expected records 1 and 3 after the torn one was dropped, got %+v (err=%v)", records, err)
+	}
+}
+
+func TestOpenJournalFile_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	buf := NewBusyPollRingBuffer[int](8)
+	reader := buf.Reader()
+	journal, err := OpenJournalFile[int](path, reader, RawCodec[int]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.WriteMany([]int{1, 2, 3})
+	journal.Poll()
+	journal.Close()
+	data, _ := os.ReadFile(path)
+	record := journalRecordHeader + 8
+
+	second := journalHeaderSize + record
+	for name, corrupt := range map[string]func(b []byte){
+		"value":       func(b []byte) { b[second+journalRecordHeader] ^= 0xff },
+		"length":      func(b []byte) { binary.LittleEndian.PutUint32(b[second:], 1000) },
+		"huge length": func(b []byte) { binary.LittleEndian.PutUint32(b[second:], math.MaxUint32) },
+		"last length": func(b []byte) { binary.LittleEndian.PutUint32(b[second+record:], 1) },
+	} {
+		middle := append([]byte(nil), data...)
+		corrupt(middle)
+		os.WriteFile(path, middle, 0o644)
+		if _, err := OpenJournalFile[int](path, reader, RawCodec[int]{}); !errors.Is(err, ErrJournalCorrupt) {
+			t.Fatalf("%s: Expected ErrJournalCorrupt, got %v", name, err)
+		}
+		if got, _ := os.ReadFile(path); !bytes.Equal(got, middle) {
+			t.Fatalf("%s: Expected the journal to be left as it was, got %d bytes instead of %d", name, len(got), len(middle))
+		}
+	}
+
+	last := append([]byte(nil), data...)
+	last[len(last)-1] ^= 0xff // the value of the third record
+	os.WriteFile(path, last, 0o644)
+	if journal, err = OpenJournalFile[int](path, reader, RawCodec[int]{}); err != nil {
+		t.Fatal(err)
+	}
+	journal.Close()
+	if info, _ := os.Stat(path); info.Size() != int64(journalHeaderSize+2*record) {
+		t.Fatalf("Expected the corrupt final record to be truncated, got %d bytes", info.Size())
+	}
+}
+
+func TestJournalWriter_SetTimeFunc(t *testing.T) {
+	buf := NewBusyPollRingBuffer[journalTick](8)
+	var out bytes.Buffer
+	journal, _ := NewJournalWriter[journalTick](&out, buf.Reader(), RawCodec[journalTick]{})
+	journal.SetTimeFunc(func(v *journalTick) time.Time { return time.Unix(v.N, 0) })
+	buf.WriteMany([]journalTick{{N: 100}, {N: 105}})
+	journal.Poll()
+	journal.Close()
+
+	records, err := readJournal[journalTick](t, out.Bytes(), RawCodec[journalTick]{})
+	if err != nil || len(records) != 2 || records[0].Time.Unix() != 100 || records[1].Time.Unix() != 105 {
+		t.Fatalf("Expected the items' own times, got %+v (err=%v)", records, err)
+	}
+}
+
+func TestJournalWriter_Run(t *testing.T) {
+	buf := NewBusyPollRingBuffer[int](64)
+	reader := buf.Reader()
+	reader.SetWaitStrategy(ParkWait(0, 0))
+	var out bytes.Buffer
+	journal, _ := NewJournalWriter[int](&out, reader, RawCodec[int]{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- journal.Run(ctx)
+	}()
+	for i := 0; i < 20; i++ {
+		if i%5 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		buf.Write(i)
+	}
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	records, err := readJournal[int](t, out.Bytes(), RawCodec[int]{})
+	if err != nil || len(records) != 20 {
+		t.Fatalf("Expected 20 records, got %d (err=%v)", len(records), err)
+	}
+	for i, rec := range records {
+		if rec.Seq != uint64(i) || rec.Value != i {
+			t.Fatalf("record %d: %+v", i, rec)
+		}
+	}
+}
+
+func TestReplayJournal_Pacing(t *testing.T) {
+	buf := NewBusyPollRingBuffer[int](8)
+	var out bytes.Buffer
+	journal, _ := NewJournalWriter[int](&out, buf.Reader(), RawCodec[int]{})
+	buf.Write(1)
+	journal.Poll()
+	time.Sleep(40 * time.Millisecond)
+	buf.Write(2)
+	journal.Poll()
+	journal.Close()
+
+	replay := func(speed float64) time.Duration {
+		r, _ := NewJournalReader[int](bytes.NewReader(out.Bytes()), RawCodec[int]{})
+		start := time.Now()
+		if n, err := ReplayJournal(context.Background(), r, NewBusyPollRingBuffer[int](8), speed); err != nil || n != 2 {
+			t.Fatalf("ReplayJournal want 2 got %d (err=%v)", n, err)
+		}
+		return time.Since(start)
+	}
+	if elapsed := replay(1); elapsed < 40*time.Millisecond {
+		t.Fatalf("Replay at original pacing took only %v", elapsed)
+	}
+	if elapsed := replay(0); elapsed >= 40*time.Millisecond {
+		t.Fatalf("Replay as fast as possible took %v", elapsed)
+	}
+
+	r, _ := NewJournalReader[int](bytes.NewReader(out.Bytes()), RawCodec[int]{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if n, err := ReplayJournal(ctx, r, NewBusyPollRingBuffer[int](8), 1); !errors.Is(err, context.DeadlineExceeded) || n != 1 {
+		t.Fatalf("Expected 1 item and context.DeadlineExceeded, got %d (err=%v)", n, err)
+	}
+}
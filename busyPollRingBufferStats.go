@@ -0,0 +1,156 @@
+package quantainer
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+type (
+	// BusyPollRingBufferStats is a snapshot of the metrics of a BusyPollRingBuffer and its registered readers.
+	BusyPollRingBufferStats struct {
+		Len     int    // size of the buffer; readers start losing items once they lag Len-1 items behind
+		Writes  uint64 // total number of items written
+		Readers []BusyPollRingBufferReaderStats
+	}
+
+	// BusyPollRingBufferReaderStats are the metrics of a reader registered with RegisterReader.
+	BusyPollRingBufferReaderStats struct {
+		Name      string
+		Next      uint64 // sequence number of the next read
+		Lag       uint64 // number of items written but not read yet
+		HighWater uint64 // largest lag seen by a read
+		Overruns  uint64 // number of times the reader was lapped by the writer
+		Lost      uint64 // number of items overwritten before the reader could read them
+	}
+
+	// busyPollReaderStats are updated by the reader and read by Stats from any goroutine.
+	busyPollReaderStats struct {
+		name      string
+		next      atomic.Uint64
+		highWater atomic.Uint64
+		overruns  atomic.Uint64
+		lost      atomic.Uint64
+	}
+)
+
+// RegisterReader creates a reader like Reader whose lag and overruns are reported by Stats under name.
+// Call Unregister when the reader is no longer used.
+func (me *busyPollRing[T]) RegisterReader(name string) *BusyPollRingBufferReader[T] {
+	result := me.Reader()
+	result.stats = &busyPollReaderStats{name: name}
+	result.stats.next.Store(result.next)
+	me.statsMu.Lock()
+	me.readers = append(me.readers, result.stats)
+	me.statsMu.Unlock()
+	return result
+}
+
+// Unregister removes a reader created by RegisterReader from the buffer's Stats.
+func (me *BusyPollRingBufferReader[T]) Unregister() {
+	if me.stats == nil {
+		return
+	}
+	buf := me.buf
+	buf.statsMu.Lock()
+	for i, stats := range buf.readers {
+		if stats == me.stats {
+			buf.readers = append(buf.readers[:i], buf.readers[i+1:]...)
+			break
+		}
+	}
+	buf.statsMu.Unlock()
+	me.stats = nil
+}
+
+func (me *busyPollReaderStats) observe(lag uint64) {
+	if lag > me.highWater.Load() {
+		me.highWater.Store(lag)
+	}
+}
+
+// Stats returns the current metrics of the buffer and its registered readers. It is safe to call from any goroutine.
+func (me *busyPollRing[T]) Stats() (result BusyPollRingBufferStats) {
+	result.Len = me.Len()
+	result.Writes = me.seq.Load()
+	me.statsMu.Lock()
+	defer me.statsMu.Unlock()
+	result.Readers = make([]BusyPollRingBufferReaderStats, len(me.readers))
+	for i, stats := range me.readers {
+		next := stats.next.Load()
+		r := &result.Readers[i]
+		r.Name = stats.name
+		r.Next = next
+		if next < result.Writes {
+			r.Lag = result.Writes - next
+		}
+		r.HighWater = stats.highWater.Load()
+		r.Overruns = stats.overruns.Load()
+		r.Lost = stats.lost.Load()
+	}
+	return
+}
+
+// PublishExpvar publishes the Stats of the buffer as the expvar variable name.
+// Like expvar.Publish, it panics if name is already in use.
+func (me *busyPollRing[T]) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return me.Stats()
+	}))
+}
+
+var prometheusLabel = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteBusyPollRingBufferMetrics writes the stats of the buffers in the Prometheus text exposition format,
+// labelling the metrics of each buffer with its name in the map.
+func WriteBusyPollRingBufferMetrics(w io.Writer, stats map[string]BusyPollRingBufferStats) error {
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	metrics := []struct {
+		name, kind, help string
+		buffer           func(s *BusyPollRingBufferStats) uint64
+		reader           func(r *BusyPollRingBufferReaderStats) uint64
+	}{
+		{"quantainer_ring_buffer_size", "gauge", "Number of slots of the ring buffer.",
+			func(s *BusyPollRingBufferStats) uint64 { return uint64(s.Len) }, nil},
+		{"quantainer_ring_buffer_writes_total", "counter", "Total number of items written.",
+			func(s *BusyPollRingBufferStats) uint64 { return s.Writes }, nil},
+		{"quantainer_ring_buffer_reader_lag", "gauge", "Number of items written but not read yet.",
+			nil, func(r *BusyPollRingBufferReaderStats) uint64 { return r.Lag }},
+		{"quantainer_ring_buffer_reader_lag_high_water", "gauge", "Largest lag seen by a read.",
+			nil, func(r *BusyPollRingBufferReaderStats) uint64 { return r.HighWater }},
+		{"quantainer_ring_buffer_reader_overruns_total", "counter", "Number of times the reader was lapped by the writer.",
+			nil, func(r *BusyPollRingBufferReaderStats) uint64 { return r.Overruns }},
+		{"quantainer_ring_buffer_reader_lost_total", "counter", "Number of items overwritten before the reader could read them.",
+			nil, func(r *BusyPollRingBufferReaderStats) uint64 { return r.Lost }},
+	}
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind); err != nil {
+			return err
+		}
+		for _, name := range names {
+			s := stats[name]
+			label := prometheusLabel.Replace(name)
+			if m.buffer != nil {
+				if _, err := fmt.Fprintf(w, "%s{buffer=\"%s\"} %d\n", m.name, label, m.buffer(&s)); err != nil {
+					return err
+				}
+				continue
+			}
+			for i := range s.Readers {
+				r := &s.Readers[i]
+				if _, err := fmt.Fprintf(w, "%s{buffer=\"%s\",reader=\"%s\"} %d\n", m.name, label, prometheusLabel.Replace(r.Name), m.reader(r)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package quantainer
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBusyPollRingBuffer_Stats(t *testing.T) {
+	buf := NewBusyPollRingBuffer[int](4)
+	fast := buf.RegisterReader("fast")
+	slow := buf.RegisterReader("slow")
+	buf.Reader() // unregistered readers are not reported
+
+	var val int
+	for i := 0; i < 3; i++ {
+		buf.Write(i)
+	}
+	for fast.Read(&val) {
+	}
+	for i := 3; i < 8; i++ {
+		buf.Write(i)
+	}
+	fast.ReadMany(make([]int, 2))
+
+	stats := buf.Stats()
+	if stats.Len != 4 || stats.Writes != 8 || len(stats.Readers) != 2 {
+		t.Fatalf("Unexpected stats %+v", stats)
+	}
+	// fast read 0..2, then 5 and 6 after losing 3 and 4
+	if r := stats.Readers[0]; r != (BusyPollRingBufferReaderStats{Name: "fast", Next: 7, Lag: 1, HighWater: 5, Overruns: 1, Lost: 2}) {
+		t.Fatalf("Unexpected fast reader stats %+v", r)
+	}
+	if r := stats.Readers[1]; r != (BusyPollRingBufferReaderStats{Name: "slow", Next: 0, Lag: 8}) {
+		t.Fatalf("Unexpected slow reader stats %+v", r)
+	}
+
+	if _, lost, ok := slow.ReadSeq(&val); ok || lost != 5 {
+		t.Fatalf("Expected 5 items lost, got lost=%d ok=%v", lost, ok)
+	}
+	slow.Unregister()
+	stats = buf.Stats()
+	if len(stats.Readers) != 1 || stats.Readers[0].Name != "fast" {
+		t.Fatalf("Expected only the fast reader after Unregister, got %+v", stats.Readers)
+	}
+}
+
+func TestWriteBusyPollRingBufferMetrics(t *testing.T) {
+	buf := NewMultiProducerBusyPollRingBuffer[int](8)
+	reader := buf.RegisterReader(`strat"1"`)
+	buf.WriteMany([]int{1, 2, 3})
+	var val int
+	reader.Read(&val)
+
+	var out strings.Builder
+	if err := WriteBusyPollRingBufferMetrics(&out, map[string]BusyPollRingBufferStats{"ticks": buf.Stats()}); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"# TYPE quantainer_ring_buffer_writes_total counter",
+		`quantainer_ring_buffer_size{buffer="ticks"} 8`,
+		`quantainer_ring_buffer_writes_total{buffer="ticks"} 3`,
+		`quantainer_ring_buffer_reader_lag{buffer="ticks",reader="strat\"1\""} 2`,
+		`quantainer_ring_buffer_reader_lag_high_water{buffer="ticks",reader="strat\"1\""} 3`,
+		`quantainer_ring_buffer_reader_lost_total{buffer="ticks",reader="strat\"1\""} 0`,
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Fatalf("Expected line %q in\n%s", line, out.String())
+		}
+	}
+}
+
+// expvarRuns makes the published names unique, as expvar panics on a name published twice, e.g. with -count=2.
+var expvarRuns atomic.Int64
+
+func TestBusyPollRingBuffer_PublishExpvar(t *testing.T) {
+	buf := NewBusyPollRingBuffer[int](4)
+	buf.RegisterReader("r")
+	buf.Write(1)
+	name := fmt.Sprintf("quantainer_test_ring_%d", expvarRuns.Add(1))
+	buf.PublishExpvar(name)
+
+	var stats BusyPollRingBufferStats
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Writes != 1 || len(stats.Readers) != 1 || stats.Readers[0].Lag != 1 {
+		t.Fatalf("Unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,148 @@
+package quantainer
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"time"
+)
+
+// ErrWaitTimeout is returned by ReadWait when no data arrives within WaitStrategy.Timeout.
+var ErrWaitTimeout = errors.New("quantainer: wait timed out")
+
+// WaitStrategy configures how BusyPollRingBufferReader.ReadWait waits for new data.
+// It busy polls Spin times (forever if Spin is negative), then polls Yield times calling runtime.Gosched in between,
+// then parks until the writer publishes more data if Park is set, or keeps yielding if not.
+// Readers attached to the buffer of another process never park.
+// If Timeout is positive, ReadWait gives up with ErrWaitTimeout after waiting that long.
+//
+// The zero value yields from the first unsuccessful poll on and never times out.
+type WaitStrategy struct {
+	Spin    int
+	Yield   int
+	Park    bool
+	Timeout time.Duration
+}
+
+// BusySpinWait busy polls without ever giving up the CPU. Lowest latency, burns a full core per reader.
+func BusySpinWait() WaitStrategy {
+	return WaitStrategy{Spin: -1}
+}
+
+// YieldWait busy polls spin times, then calls runtime.Gosched between polls.
+func YieldWait(spin int) WaitStrategy {
+	return WaitStrategy{Spin: spin}
+}
+
+// ParkWait busy polls spin times, yields yield times, then parks until the writer publishes more data.
+// Parked readers cost the writer a lock and a channel close per write.
+func ParkWait(spin, yield int) WaitStrategy {
+	return WaitStrategy{Spin: spin, Yield: yield, Park: true}
+}
+
+// WithTimeout returns a copy of the strategy that gives up after timeout.
+func (me WaitStrategy) WithTimeout(timeout time.Duration) WaitStrategy {
+	me.Timeout = timeout
+	return me
+}
+
+// SetWaitStrategy sets the strategy used by ReadWait.
+func (me *BusyPollRingBufferReader[T]) SetWaitStrategy(wait WaitStrategy) {
+	me.wait = wait
+}
+
+// ReadWait reads the next item into result, waiting for it according to the reader's WaitStrategy.
+// Like Read, it silently skips items overwritten before they could be read.
+// It returns ctx.Err() if ctx is done, or ErrWaitTimeout if the strategy's Timeout expires first.
+func (me *BusyPollRingBufferReader[T]) ReadWait(ctx context.Context, result *T) error {
+	if me.Read(result) {
+		return nil
+	}
+
+	wait := me.wait
+	done := ctx.Done()
+	var deadline time.Time
+	if wait.Timeout > 0 {
+		deadline = time.Now().Add(wait.Timeout)
+	}
+	for idle := 0; ; idle++ {
+		if me.Read(result) {
+			return nil
+		}
+		select {
+		case <-done:
+			return ctx.Err()
+		default:
+		}
+		spinning := wait.Spin < 0 || idle < wait.Spin
+		if wait.Timeout > 0 && (!spinning || idle%64 == 0) && !time.Now().Before(deadline) {
+			return ErrWaitTimeout
+		}
+		switch {
+		case spinning:
+		case !wait.Park || me.buf.remote || idle < wait.Spin+wait.Yield:
+			runtime.Gosched()
+		default:
+			if err := me.park(ctx, deadline); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// pending returns true if Read would not come back empty.
+func (me *BusyPollRingBufferReader[T]) pending() bool {
+	buf := me.buf
+	head := buf.seq.Load()
+	if me.next >= head {
+		return false
+	}
+	if me.next < buf.oldest(head) {
+		return true
+	}
+	return buf.versions[me.current].Load() >= 2*me.next+2
+}
+
+// park blocks until the writer publishes more data, ctx is done or the deadline (if not zero) passes.
+func (me *BusyPollRingBufferReader[T]) park(ctx context.Context, deadline time.Time) error {
+	buf := me.buf
+	buf.waiters.Add(1)
+	defer buf.waiters.Add(-1)
+
+	buf.mu.Lock()
+	if buf.notify == nil {
+		buf.notify = make(chan struct{})
+	}
+	notify := buf.notify
+	buf.mu.Unlock()
+
+	// the writer only wakes readers registered before it publishes, so check again after registering
+	if me.pending() {
+		return nil
+	}
+
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-notify:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timeout:
+		return ErrWaitTimeout
+	}
+}
+
+// wake wakes up all parked readers.
+func (me *busyPollRing[T]) wake() {
+	me.mu.Lock()
+	if me.notify != nil {
+		close(me.notify)
+		me.notify = nil
+	}
+	me.mu.Unlock()
+}
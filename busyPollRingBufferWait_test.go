@@ -0,0 +1,97 @@
+package quantainer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBusyPollRingBuffer_ReadWaitAvailable(t *testing.T) {
+	buf := NewBusyPollRingBuffer[int](4)
+	reader := buf.Reader()
+	buf.Write(1)
+
+	var val int
+	if err := reader.ReadWait(context.Background(), &val); err != nil || val != 1 {
+		t.Fatalf("Expected 1, got %d (err=%v)", val, err)
+	}
+}
+
+func TestBusyPollRingBuffer_ReadWaitTimeout(t *testing.T) {
+	strategies := map[string]WaitStrategy{
+		"spin":  BusySpinWait(),
+		"yield": YieldWait(10),
+		"park":  ParkWait(10, 10),
+	}
+	for name, wait := range strategies {
+		t.Run(name, func(t *testing.T) {
+			buf := NewBusyPollRingBuffer[int](4)
+			reader := buf.Reader()
+			reader.SetWaitStrategy(wait.WithTimeout(20 * time.Millisecond))
+
+			start := time.Now()
+			var val int
+			if err := reader.ReadWait(context.Background(), &val); !errors.Is(err, ErrWaitTimeout) {
+				t.Fatalf("Expected ErrWaitTimeout, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+				t.Fatalf("Timed out too early after %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestBusyPollRingBuffer_ReadWaitCanceled(t *testing.T) {
+	buf := NewBusyPollRingBuffer[int](4)
+	reader := buf.Reader()
+	reader.SetWaitStrategy(ParkWait(0, 0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	var val int
+	if err := reader.ReadWait(ctx, &val); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestBusyPollRingBuffer_ReadWaitParkWakesUp(t *testing.T) {
+	const n = 1000
+	buf := NewBusyPollRingBuffer[int](n + 1)
+	reader := buf.Reader()
+	reader.SetWaitStrategy(ParkWait(0, 0).WithTimeout(5 * time.Second))
+
+	go func() {
+		for i := 0; i < n; i++ {
+			if i%100 == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			buf.Write(i)
+		}
+	}()
+
+	var val int
+	for i := 0; i < n; i++ {
+		if err := reader.ReadWait(context.Background(), &val); err != nil || val != i {
+			t.Fatalf("Expected %d, got %d (err=%v)", i, val, err)
+		}
+	}
+}
+
+func TestMultiProducerBusyPollRingBuffer_ReadWaitParkWakesUp(t *testing.T) {
+	buf := NewMultiProducerBusyPollRingBuffer[int](4)
+	reader := buf.Reader()
+	reader.SetWaitStrategy(ParkWait(0, 0).WithTimeout(5 * time.Second))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		ptr, seq := buf.BeginWrite()
+		*ptr = 42
+		buf.EndWrite(seq)
+	}()
+
+	var val int
+	if err := reader.ReadWait(context.Background(), &val); err != nil || val != 42 {
+		t.Fatalf("Expected 42, got %d (err=%v)", val, err)
+	}
+}
@@ -1,6 +1,10 @@
 package quantainer
 
 import (
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -203,12 +207,16 @@ func TestBusyPollRingBuffer_ReaderWrapsAround(t *testing.T) {
 	buf.Write(5) // pos 1, from=1, to=2
 
 	// After the writes: from=1, to=2
-	// Reader current=2, but it missed value at pos 0 (which is 4)
-	// Reader can't read because current(2) == to(2)
-	// This demonstrates the "readers would miss data if they are too slow" behavior
+	// Reader current=2, but value 3 at pos 2 is reserved for the next write
+	// This demonstrates the "readers would miss data if they are too slow" behavior:
+	// the reader skips 3 and continues from the oldest item still available
 	ok := reader.Read(&val)
-	if ok {
-		t.Error("Expected no data - reader was too slow and missed data")
+	if !ok || val != 4 {
+		t.Errorf("Expected 4 - reader was too slow and missed 3, got %d (ok=%v)", val, ok)
+	}
+	ok = reader.Read(&val)
+	if !ok || val != 5 {
+		t.Errorf("Expected 5, got %d (ok=%v)", val, ok)
 	}
 }
 
@@ -438,6 +446,296 @@ func TestBusyPollRingBuffer_BeginWritePointerStability(t *testing.T) {
 	}
 }
 
+func TestBusyPollRingBuffer_ReadSeq(t *testing.T) {
+	buf := NewBusyPollRingBuffer[int](4)
+	reader := buf.Reader()
+
+	buf.Write(10)
+	buf.WriteMany([]int{20, 30})
+	if buf.Seq() != 3 {
+		t.Fatalf("Seq want 3 got %d", buf.Seq())
+	}
+
+	var val int
+	for i, exp := range []int{10, 20, 30} {
+		seq, lost, ok := reader.ReadSeq(&val)
+		if !ok || lost != 0 || seq != uint64(i) || val != exp {
+			t.Fatalf("Read %d: got seq=%d lost=%d ok=%v val=%d", i, seq, lost, ok, val)
+		}
+	}
+	if _, lost, ok := reader.ReadSeq(&val); ok || lost != 0 {
+		t.Fatalf("Expected no more data, got lost=%d ok=%v", lost, ok)
+	}
+	if reader.Next() != 3 {
+		t.Fatalf("Next want 3 got %d", reader.Next())
+	}
+}
+
+func TestBusyPollRingBuffer_Overrun(t *testing.T) {
+	buf := NewBusyPollRingBuffer[int](4)
+	reader := buf.Reader()
+
+	// 3 items fit (one slot is reserved for the writer), the 4th and 5th overwrite seq 0 and 1
+	for i := 0; i < 5; i++ {
+		buf.Write(i)
+	}
+
+	var val int
+	seq, lost, ok := reader.ReadSeq(&val)
+	if ok || lost != 2 {
+		t.Fatalf("Expected overrun with 2 items lost, got seq=%d lost=%d ok=%v", seq, lost, ok)
+	}
+	// the reader resumes from the oldest item still available
+	for _, exp := range []int{2, 3, 4} {
+		seq, lost, ok = reader.ReadSeq(&val)
+		if !ok || lost != 0 || seq != uint64(exp) || val != exp {
+			t.Fatalf("Expected seq %d, got seq=%d lost=%d ok=%v val=%d", exp, seq, lost, ok, val)
+		}
+	}
+	if _, _, ok = reader.ReadSeq(&val); ok {
+		t.Fatal("Expected no more data")
+	}
+}
+
+func TestBusyPollRingBuffer_ReaderStartsAtLastWrite(t *testing.T) {
+	buf := NewBusyPollRingBuffer[int](4)
+	buf.WriteMany([]int{1, 2, 3, 4, 5, 6})
+
+	// the last write is larger than the buffer, so the reader starts from the oldest item available
+	reader := buf.Reader()
+	if reader.Next() != 3 {
+		t.Fatalf("Next want 3 got %d", reader.Next())
+	}
+	var val int
+	if seq, _, ok := reader.ReadSeq(&val); !ok || seq != 3 || val != 4 {
+		t.Fatalf("Expected seq 3 val 4, got seq=%d ok=%v val=%d", seq, ok, val)
+	}
+}
+
+// Concurrent readers must never see a torn item, and every sequence number is either read or reported lost.
+func TestBusyPollRingBuffer_Concurrent(t *testing.T) {
+	type item struct {
+		A, B int64
+		S    string
+	}
+	const n = 200000
+	buf := NewBusyPollRingBuffer[item](64)
+	readers := make([]*BusyPollRingBufferReader[item], 4)
+	for i := range readers {
+		readers[i] = buf.Reader()
+	}
+
+	var wg sync.WaitGroup
+	var done atomic.Bool
+	for _, reader := range readers {
+		wg.Add(1)
+		go func(reader *BusyPollRingBufferReader[item]) {
+			defer wg.Done()
+			var val item
+			var expected, read, lost uint64
+			for {
+				finished := done.Load()
+				seq, l, ok := reader.ReadSeq(&val)
+				switch {
+				case ok:
+					if seq != expected || val.A != int64(seq) || val.B != -val.A || val.S != strconv.FormatInt(val.A, 10) {
+						t.Errorf("seq %d (expected %d): torn or out of order item %+v", seq, expected, val)
+						return
+					}
+					expected++
+					read++
+				case l != 0:
+					expected += l
+					lost += l
+				case finished:
+					if read+lost != n {
+						t.Errorf("read %d + lost %d != written %d", read, lost, n)
+					}
+					return
+				}
+			}
+		}(reader)
+	}
+
+	for i := int64(0); i < n; i++ {
+		if i%2 == 0 {
+			buf.Write(item{A: i, B: -i, S: strconv.FormatInt(i, 10)})
+		} else {
+			ptr := buf.BeginWrite()
+			ptr.A = i
+			ptr.B = -i
+			ptr.S = strconv.FormatInt(i, 10)
+			buf.EndWrite()
+		}
+	}
+	done.Store(true)
+	wg.Wait()
+}
+
+// checkWholeReads writes n items while a reader reads them, and checks that the reader gets them whole and in order.
+// index returns the index of an item, and false if it is torn.
+func checkWholeReads[T any](t *testing.T, word uintptr, item func(i int64) T, index func(v T) (int64, bool)) {
+	t.Helper()
+	const n = 50000
+	buf := NewBusyPollRingBuffer[T](16)
+	if buf.word != word {
+		t.Fatalf("%T is copied by words of %d bytes, want %d", *new(T), buf.word, word)
+	}
+	reader := buf.Reader()
+	var wg sync.WaitGroup
+	var done atomic.Bool
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var val T
+		last := int64(-1)
+		for {
+			finished := done.Load()
+			if !reader.Read(&val) {
+				if finished {
+					return
+				}
+				runtime.Gosched()
+				continue
+			}
+			i, ok := index(val)
+			if !ok || i <= last {
+				t.Errorf("torn or out of order item %+v after %d", val, last)
+				return
+			}
+			last = i
+		}
+	}()
+	for i := int64(0); i < n; i++ {
+		buf.Write(item(i))
+	}
+	done.Store(true)
+	wg.Wait()
+}
+
+func TestBusyPollRingBuffer_CopyModes(t *testing.T) {
+	type words8 struct{ A, B, C int64 }
+	type words4 struct{ A, B, C int32 }
+	type odd struct{ A, B, C int16 }
+	checkWholeReads(t, 8, func(i int64) words8 { return words8{i, -i, i} },
+		func(v words8) (int64, bool) { return v.A, v.B == -v.A && v.C == v.A })
+	checkWholeReads(t, 4, func(i int64) words4 { return words4{int32(i), int32(-i), int32(i)} },
+		func(v words4) (int64, bool) { return int64(v.A), v.B == -v.A && v.C == v.A })
+	checkWholeReads(t, 0, func(i int64) odd { return odd{int16(i), int16(-i), int16(i)} },
+		func(v odd) (int64, bool) { return int64(uint16(v.A)), v.B == -v.A && v.C == v.A })
+}
+
+// Readers of items copied under slot locks must not fail while another reader is copying the same item.
+func TestBusyPollRingBuffer_SharedSlotLock(t *testing.T) {
+	buf := NewBusyPollRingBuffer[string](4)
+	reader := buf.Reader()
+	buf.WriteMany([]string{"a", "b"})
+	buf.locks[0].RLock() // another reader in the middle of copying "a"
+	defer buf.locks[0].RUnlock()
+
+	var val string
+	if !reader.Read(&val) || val != "a" {
+		t.Fatalf("Expected a, got %q", val)
+	}
+	dst := make([]string, 2)
+	if n := reader.ReadMany(dst); n != 1 || dst[0] != "b" {
+		t.Fatalf("Expected [b], got %v", dst[:n])
+	}
+}
+
+func TestBusyPollRingBuffer_ReadMany(t *testing.T) {
+	buf := NewBusyPollRingBuffer[int](5)
+	reader := buf.Reader()
+	dst := make([]int, 3)
+
+	if n := reader.ReadMany(dst); n != 0 {
+		t.Fatalf("Expected no data, got %d items", n)
+	}
+
+	buf.WriteMany([]int{1, 2, 3, 4})
+	if n := reader.ReadMany(dst); n != 3 || !equalSlice(dst, []int{1, 2, 3}) {
+		t.Fatalf("Expected [1 2 3], got %v (n=%d)", dst[:n], n)
+	}
+
+	// wraps around the end of the buffer
+	buf.WriteMany([]int{5, 6})
+	if n := reader.ReadMany(dst); n != 3 || !equalSlice(dst, []int{4, 5, 6}) {
+		t.Fatalf("Expected [4 5 6], got %v (n=%d)", dst[:n], n)
+	}
+
+	// lapped: 7 and 8 are lost, only the last 4 items are available
+	buf.WriteMany([]int{7, 8, 9, 10, 11, 12})
+	big := make([]int, 10)
+	if n := reader.ReadMany(big); n != 4 || !equalSlice(big[:n], []int{9, 10, 11, 12}) {
+		t.Fatalf("Expected [9 10 11 12], got %v (n=%d)", big[:n], n)
+	}
+	if reader.Next() != buf.Seq() {
+		t.Fatalf("Next want %d got %d", buf.Seq(), reader.Next())
+	}
+}
+
+// Batch reads under concurrent writes must only return intact items, in increasing order.
+func TestBusyPollRingBuffer_ConcurrentBatch(t *testing.T) {
+	type item struct {
+		A, B int64
+	}
+	const n = 50000
+	buf := NewBusyPollRingBuffer[item](64)
+
+	var wg sync.WaitGroup
+	var done atomic.Bool
+	// batches of different sizes wrap around the end of the buffer at different places
+	for _, size := range []int{16, 5, 64} {
+		wg.Add(1)
+		go func(reader *BusyPollRingBufferReader[item], dst []item) {
+			defer wg.Done()
+			last := int64(-1)
+			for {
+				finished := done.Load()
+				k := reader.ReadMany(dst)
+				for _, v := range dst[:k] {
+					if v.B != -v.A || v.A <= last {
+						t.Errorf("ReadMany(%d): torn or out of order item %+v after %d", len(dst), v, last)
+						return
+					}
+					last = v.A
+				}
+				if k == 0 {
+					if finished {
+						return
+					}
+					runtime.Gosched()
+				}
+			}
+		}(buf.Reader(), make([]item, size))
+	}
+
+	for i := int64(0); i < n; i++ {
+		buf.Write(item{A: i, B: -i})
+	}
+	done.Store(true)
+	wg.Wait()
+}
+
+func TestMultiProducerBusyPollRingBuffer_ReadManyStopsAtUncommitted(t *testing.T) {
+	buf := NewMultiProducerBusyPollRingBuffer[int](8)
+	reader := buf.Reader()
+
+	buf.Write(1)
+	ptr, seq := buf.BeginWrite()
+	buf.Write(3)
+
+	dst := make([]int, 4)
+	if n := reader.ReadMany(dst); n != 1 || dst[0] != 1 {
+		t.Fatalf("Expected [1], got %v", dst[:n])
+	}
+	*ptr = 2
+	buf.EndWrite(seq)
+	if n := reader.ReadMany(dst); n != 2 || !equalSlice(dst[:n], []int{2, 3}) {
+		t.Fatalf("Expected [2 3], got %v", dst[:n])
+	}
+}
+
 func BenchmarkBusyPollRingBuffer_Write(b *testing.B) {
 	buf := NewBusyPollRingBuffer[int](1000)
 	b.ResetTimer()
@@ -495,3 +793,15 @@ func BenchmarkBusyPollRingBuffer_BeginEndWrite(b *testing.B) {
 		buf.EndWrite()
 	}
 }
+
+func BenchmarkBusyPollRingBuffer_ReadMany(b *testing.B) {
+	buf := NewBusyPollRingBuffer[int](1024)
+	reader := buf.Reader()
+	data := make([]int, 64)
+	dst := make([]int, 64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i += len(data) {
+		buf.WriteMany(data)
+		reader.ReadMany(dst)
+	}
+}
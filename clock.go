@@ -0,0 +1,48 @@
+package quantainer
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Clock tells the current time to containers that expire elements by age,
+	// so they can run on exchange or backtest time instead of the wall clock.
+	Clock interface {
+		Now() time.Time
+	}
+
+	systemClock struct{}
+
+	// ManualClock is a Clock that only moves when told to, for tests and backtests driven by data timestamps.
+	// It is safe for concurrent use.
+	ManualClock struct {
+		now atomic.Int64
+	}
+)
+
+// SystemClock is the Clock returning time.Now().
+var SystemClock Clock = systemClock{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func NewManualClock(now time.Time) *ManualClock {
+	result := &ManualClock{}
+	result.Set(now)
+	return result
+}
+
+func (me *ManualClock) Now() time.Time {
+	return time.Unix(0, me.now.Load())
+}
+
+func (me *ManualClock) Set(now time.Time) {
+	me.now.Store(now.UnixNano())
+}
+
+// Advance moves the clock forward by d and returns the new time.
+func (me *ManualClock) Advance(d time.Duration) time.Time {
+	return time.Unix(0, me.now.Add(int64(d)))
+}
@@ -0,0 +1,26 @@
+package quantainer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	c := NewManualClock(start)
+	if !c.Now().Equal(start) {
+		t.Fatalf("Now want %v got %v", start, c.Now())
+	}
+	if now := c.Advance(time.Second); !now.Equal(start.Add(time.Second)) || !c.Now().Equal(now) {
+		t.Fatalf("Advance want %v got %v", start.Add(time.Second), now)
+	}
+	c.Set(start)
+	if !c.Now().Equal(start) {
+		t.Fatalf("Set want %v got %v", start, c.Now())
+	}
+
+	before := time.Now()
+	if now := SystemClock.Now(); now.Before(before) {
+		t.Fatalf("SystemClock went backwards: %v < %v", now, before)
+	}
+}
@@ -0,0 +1,430 @@
+package quantainer
+
+import (
+	"math"
+	"time"
+)
+
+type (
+	// Decay sets how fast the exponentially weighted statistics forget: the weight of an observation
+	// shrinks by a constant factor with every later observation, or with elapsed time for DecayTimeHalfLife.
+	// Weights are normalized by their sum, so early values are not biased towards zero.
+	Decay struct {
+		alpha    float64
+		halfLife time.Duration
+	}
+
+	// ewWeights is the decay, clock and sums of weights shared by the exponentially weighted statistics.
+	ewWeights struct {
+		decay  Decay
+		warmUp int
+		clock  Clock
+		count  int
+		last   time.Time // latest timestamp, for time-based decay
+		w, w2  float64   // sums of the weights and of their squares
+	}
+
+	// EWMA is an exponentially weighted moving average.
+	EWMA struct {
+		ew   ewWeights
+		mean float64
+	}
+
+	// EWVariance is an exponentially weighted mean and variance, updated with West's weighted algorithm.
+	EWVariance struct {
+		ew   ewWeights
+		mean float64
+		s    float64 // weighted sum of squared deviations from the mean
+	}
+
+	// EWCovariance is the exponentially weighted covariance and correlation of two aligned series.
+	// Beta regresses y on x, like RollingCovariance.
+	EWCovariance struct {
+		ew           ewWeights
+		meanX, meanY float64
+		sX, sY, cXY  float64
+	}
+
+	// EWZScore scores each value against the exponentially weighted mean and standard deviation
+	// of the values before it.
+	EWZScore struct {
+		v EWVariance
+		z float64
+	}
+)
+
+// DecayAlpha keeps 1-alpha of the weight of the past at every observation, with 0 < alpha <= 1.
+func DecayAlpha(alpha float64) Decay {
+	return Decay{alpha: alpha}
+}
+
+// DecaySpan is DecayAlpha(2 / (span + 1)), the decay of an N-period EMA.
+func DecaySpan(span float64) Decay {
+	return Decay{alpha: 2 / (span + 1)}
+}
+
+// DecayHalfLife halves the weight of an observation every halfLife observations.
+func DecayHalfLife(halfLife float64) Decay {
+	return Decay{alpha: -math.Expm1(-math.Ln2 / halfLife)}
+}
+
+// DecayTimeHalfLife halves the weight of an observation every halfLife of time, for irregularly spaced values.
+// Values with the same timestamp weigh the same. A timestamp earlier than the latest one is taken as the latest,
+// like OutOfOrderClamp.
+func DecayTimeHalfLife(halfLife time.Duration) Decay {
+	return Decay{halfLife: halfLife}
+}
+
+func (me Decay) valid() bool {
+	return me.halfLife > 0 || me.alpha > 0 && me.alpha <= 1
+}
+
+func newEWWeights(decay Decay, warmUp int, clock Clock) ewWeights {
+	if !decay.valid() {
+		panic("quantainer: invalid Decay")
+	}
+	return ewWeights{
+		decay:  decay,
+		warmUp: warmUp,
+		clock:  clock,
+	}
+}
+
+// now returns the time Add timestamps values with; only time-based decay reads the clock.
+func (me *ewWeights) now() (result time.Time) {
+	if me.decay.halfLife > 0 {
+		result = me.clock.Now()
+	}
+	return
+}
+
+// step decays the weights to tm and adds the weight 1 of a new observation,
+// returning the factor the previous weights were multiplied by.
+func (me *ewWeights) step(tm time.Time) (d float64) {
+	if me.count == 0 {
+		me.last = tm
+	} else if me.decay.halfLife <= 0 {
+		d = 1 - me.decay.alpha
+	} else if dt := tm.Sub(me.last); dt > 0 {
+		d = math.Exp2(-float64(dt) / float64(me.decay.halfLife))
+		me.last = tm
+	} else {
+		d = 1
+	}
+	me.count++
+	me.w = d*me.w + 1
+	me.w2 = d*d*me.w2 + 1
+	return
+}
+
+// filled returns true once warmUp values were added.
+func (me *ewWeights) filled() bool {
+	return me.count != 0 && me.count >= me.warmUp
+}
+
+// sampleDenominator returns the sum of weights corrected for bias with reliability weights,
+// or 0 if there is a single effective observation.
+func (me *ewWeights) sampleDenominator() float64 {
+	if me.count == 0 {
+		return 0
+	}
+	return math.Max(me.w-me.w2/me.w, 0)
+}
+
+func (me *ewWeights) reset() {
+	me.count = 0
+	me.last = time.Time{}
+	me.w, me.w2 = 0, 0
+}
+
+// NewEWMA creates an EWMA that is Filled after warmUp values.
+// It panics if decay is invalid.
+func NewEWMA(decay Decay, warmUp int) *EWMA {
+	return NewEWMAWithClock(decay, warmUp, SystemClock)
+}
+
+// NewEWMAWithClock creates an EWMA whose Add timestamps values with clock, for time-based decay.
+func NewEWMAWithClock(decay Decay, warmUp int, clock Clock) *EWMA {
+	return &EWMA{
+		ew: newEWWeights(decay, warmUp, clock),
+	}
+}
+
+// Add adds v with the current time of the clock.
+func (me *EWMA) Add(v float64) {
+	me.AddAt(v, me.ew.now())
+}
+
+// AddAt adds v with timestamp tm, which only matters for time-based decay.
+func (me *EWMA) AddAt(v float64, tm time.Time) {
+	me.ew.step(tm)
+	me.mean += (v - me.mean) / me.ew.w
+}
+
+// Value returns the average, or NaN if no value was added.
+func (me *EWMA) Value() float64 {
+	if me.ew.count == 0 {
+		return math.NaN()
+	}
+	return me.mean
+}
+
+func (me *EWMA) Count() int {
+	return me.ew.count
+}
+
+// Filled returns true once warmUp values were added.
+func (me *EWMA) Filled() bool {
+	return me.ew.filled()
+}
+
+func (me *EWMA) Clear() {
+	me.ew.reset()
+	me.mean = 0
+}
+
+// NewEWVariance creates an EWVariance that is Filled after warmUp values.
+// It panics if decay is invalid.
+func NewEWVariance(decay Decay, warmUp int) *EWVariance {
+	return NewEWVarianceWithClock(decay, warmUp, SystemClock)
+}
+
+// NewEWVarianceWithClock creates an EWVariance whose Add timestamps values with clock, for time-based decay.
+func NewEWVarianceWithClock(decay Decay, warmUp int, clock Clock) *EWVariance {
+	return &EWVariance{
+		ew: newEWWeights(decay, warmUp, clock),
+	}
+}
+
+// Add adds v with the current time of the clock.
+func (me *EWVariance) Add(v float64) {
+	me.AddAt(v, me.ew.now())
+}
+
+// AddAt adds v with timestamp tm, which only matters for time-based decay.
+func (me *EWVariance) AddAt(v float64, tm time.Time) {
+	d := me.ew.step(tm)
+	delta := v - me.mean
+	me.mean += delta / me.ew.w
+	me.s = d*me.s + delta*(v-me.mean)
+}
+
+// Mean returns the weighted mean, or NaN if no value was added.
+func (me *EWVariance) Mean() float64 {
+	if me.ew.count == 0 {
+		return math.NaN()
+	}
+	return me.mean
+}
+
+// Variance returns the weighted population variance, or NaN if no value was added.
+func (me *EWVariance) Variance() float64 {
+	if me.ew.count == 0 {
+		return math.NaN()
+	}
+	return math.Max(me.s, 0) / me.ew.w
+}
+
+// SampleVariance returns the bias-corrected weighted variance, or NaN if there are not 2 values to compare.
+func (me *EWVariance) SampleVariance() float64 {
+	den := me.ew.sampleDenominator()
+	if den == 0 {
+		return math.NaN()
+	}
+	return math.Max(me.s, 0) / den
+}
+
+// StdDev returns the weighted population standard deviation.
+func (me *EWVariance) StdDev() float64 {
+	return math.Sqrt(me.Variance())
+}
+
+// ZScore returns how many standard deviations v is from the mean, or NaN if the standard deviation is 0.
+func (me *EWVariance) ZScore(v float64) float64 {
+	sd := me.StdDev()
+	if !(sd > 0) {
+		return math.NaN()
+	}
+	return (v - me.mean) / sd
+}
+
+func (me *EWVariance) Count() int {
+	return me.ew.count
+}
+
+// Filled returns true once warmUp values were added.
+func (me *EWVariance) Filled() bool {
+	return me.ew.filled()
+}
+
+func (me *EWVariance) Clear() {
+	me.ew.reset()
+	me.mean = 0
+	me.s = 0
+}
+
+// NewEWCovariance creates an EWCovariance that is Filled after warmUp pairs.
+// It panics if decay is invalid.
+func NewEWCovariance(decay Decay, warmUp int) *EWCovariance {
+	return NewEWCovarianceWithClock(decay, warmUp, SystemClock)
+}
+
+// NewEWCovarianceWithClock creates an EWCovariance whose Add timestamps pairs with clock, for time-based decay.
+func NewEWCovarianceWithClock(decay Decay, warmUp int, clock Clock) *EWCovariance {
+	return &EWCovariance{
+		ew: newEWWeights(decay, warmUp, clock),
+	}
+}
+
+// Add adds a pair with the current time of the clock.
+func (me *EWCovariance) Add(x, y float64) {
+	me.AddAt(x, y, me.ew.now())
+}
+
+// AddAt adds a pair with timestamp tm, which only matters for time-based decay.
+func (me *EWCovariance) AddAt(x, y float64, tm time.Time) {
+	d := me.ew.step(tm)
+	dx, dy := x-me.meanX, y-me.meanY
+	me.meanX += dx / me.ew.w
+	me.meanY += dy / me.ew.w
+	me.sX = d*me.sX + dx*(x-me.meanX)
+	me.sY = d*me.sY + dy*(y-me.meanY)
+	me.cXY = d*me.cXY + dx*(y-me.meanY)
+}
+
+// MeanX returns the weighted mean of x, or NaN if no pair was added.
+func (me *EWCovariance) MeanX() float64 {
+	if me.ew.count == 0 {
+		return math.NaN()
+	}
+	return me.meanX
+}
+
+// MeanY returns the weighted mean of y, or NaN if no pair was added.
+func (me *EWCovariance) MeanY() float64 {
+	if me.ew.count == 0 {
+		return math.NaN()
+	}
+	return me.meanY
+}
+
+// VarianceX returns the weighted population variance of x, or NaN if no pair was added.
+func (me *EWCovariance) VarianceX() float64 {
+	if me.ew.count == 0 {
+		return math.NaN()
+	}
+	return math.Max(me.sX, 0) / me.ew.w
+}
+
+// VarianceY returns the weighted population variance of y, or NaN if no pair was added.
+func (me *EWCovariance) VarianceY() float64 {
+	if me.ew.count == 0 {
+		return math.NaN()
+	}
+	return math.Max(me.sY, 0) / me.ew.w
+}
+
+// Covariance returns the weighted population covariance, or NaN if no pair was added.
+func (me *EWCovariance) Covariance() float64 {
+	if me.ew.count == 0 {
+		return math.NaN()
+	}
+	return me.cXY / me.ew.w
+}
+
+// SampleCovariance returns the bias-corrected weighted covariance, or NaN if there are not 2 pairs to compare.
+func (me *EWCovariance) SampleCovariance() float64 {
+	den := me.ew.sampleDenominator()
+	if den == 0 {
+		return math.NaN()
+	}
+	return me.cXY / den
+}
+
+// Correlation returns the weighted Pearson correlation in [-1, 1], or NaN if either series is constant.
+func (me *EWCovariance) Correlation() float64 {
+	if !(me.sX > 0) || !(me.sY > 0) {
+		return math.NaN()
+	}
+	r := me.cXY / math.Sqrt(me.sX*me.sY)
+	return math.Max(-1, math.Min(1, r))
+}
+
+// Beta returns the slope of the weighted least-squares regression of y on x, or NaN if x is constant.
+func (me *EWCovariance) Beta() float64 {
+	if !(me.sX > 0) {
+		return math.NaN()
+	}
+	return me.cXY / me.sX
+}
+
+func (me *EWCovariance) Count() int {
+	return me.ew.count
+}
+
+// Filled returns true once warmUp pairs were added.
+func (me *EWCovariance) Filled() bool {
+	return me.ew.filled()
+}
+
+func (me *EWCovariance) Clear() {
+	me.ew.reset()
+	me.meanX, me.meanY = 0, 0
+	me.sX, me.sY, me.cXY = 0, 0, 0
+}
+
+// NewEWZScore creates an EWZScore that is Filled once warmUp values were added before the scored one.
+// It panics if decay is invalid.
+func NewEWZScore(decay Decay, warmUp int) *EWZScore {
+	return NewEWZScoreWithClock(decay, warmUp, SystemClock)
+}
+
+// NewEWZScoreWithClock creates an EWZScore whose Add timestamps values with clock, for time-based decay.
+func NewEWZScoreWithClock(decay Decay, warmUp int, clock Clock) *EWZScore {
+	return &EWZScore{
+		v: *NewEWVarianceWithClock(decay, warmUp, clock),
+		z: math.NaN(),
+	}
+}
+
+// Add scores v against the values added before it, then adds it with the current time of the clock.
+func (me *EWZScore) Add(v float64) (z float64) {
+	return me.AddAt(v, me.v.ew.now())
+}
+
+// AddAt scores v against the values added before it, then adds it with timestamp tm.
+// z is NaN if there is no previous value or their standard deviation is 0.
+func (me *EWZScore) AddAt(v float64, tm time.Time) (z float64) {
+	if me.v.ew.count == 0 {
+		me.z = math.NaN()
+	} else {
+		me.z = me.v.ZScore(v)
+	}
+	me.v.AddAt(v, tm)
+	return me.z
+}
+
+// Value returns the score of the last value added.
+func (me *EWZScore) Value() float64 {
+	return me.z
+}
+
+// Stats returns the mean and variance the next value is scored against.
+func (me *EWZScore) Stats() *EWVariance {
+	return &me.v
+}
+
+func (me *EWZScore) Count() int {
+	return me.v.ew.count
+}
+
+// Filled returns true once the last value was scored against at least warmUp values.
+func (me *EWZScore) Filled() bool {
+	n := me.v.ew.count
+	return n > 1 && n > me.v.ew.warmUp
+}
+
+func (me *EWZScore) Clear() {
+	me.v.Clear()
+	me.z = math.NaN()
+}
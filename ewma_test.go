@@ -0,0 +1,223 @@
+package quantainer
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func ExampleEWMA() {
+	ewma := NewEWMA(DecayAlpha(0.5), 2)
+	for _, v := range []float64{1, 4, 7} {
+		ewma.Add(v)
+		fmt.Printf("%.4f %v\n", ewma.Value(), ewma.Filled())
+	}
+	// Output:
+	// 1.0000 false
+	// 3.0000 true
+	// 5.2857 true
+}
+
+// naiveEW computes the weighted statistics of xs and ys with explicit weights.
+func naiveEW(weights, xs, ys []float64) (meanX, varX, sampleVarX, cov, corr float64) {
+	var w, w2, meanY, varY float64
+	for i, wi := range weights {
+		w += wi
+		w2 += wi * wi
+		meanX += wi * xs[i]
+		meanY += wi * ys[i]
+	}
+	meanX /= w
+	meanY /= w
+	for i, wi := range weights {
+		varX += wi * (xs[i] - meanX) * (xs[i] - meanX)
+		varY += wi * (ys[i] - meanY) * (ys[i] - meanY)
+		cov += wi * (xs[i] - meanX) * (ys[i] - meanY)
+	}
+	sampleVarX = varX / (w - w2/w)
+	corr = cov / math.Sqrt(varX*varY)
+	return meanX, varX / w, sampleVarX, cov / w, corr
+}
+
+func assertEW(t *testing.T, step int, weights, xs, ys []float64, v *EWVariance, c *EWCovariance, m *EWMA) {
+	t.Helper()
+	meanX, varX, sampleVarX, cov, corr := naiveEW(weights, xs, ys)
+	for _, check := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"EWMA", m.Value(), meanX},
+		{"mean", v.Mean(), meanX},
+		{"variance", v.Variance(), varX},
+		{"sample variance", v.SampleVariance(), sampleVarX},
+		{"covariance x", c.MeanX(), meanX},
+		{"covariance", c.Covariance(), cov},
+		{"correlation", c.Correlation(), corr},
+	} {
+		if step == 0 && check.name == "sample variance" || step == 0 && check.name == "correlation" {
+			if !math.IsNaN(check.got) {
+				t.Fatalf("step %d: %s of a single value want NaN got %v", step, check.name, check.got)
+			}
+			continue
+		}
+		if math.Abs(check.got-check.want) > 1e-9*math.Max(1, math.Abs(check.want)) {
+			t.Fatalf("step %d: %s got %v want %v", step, check.name, check.got, check.want)
+		}
+	}
+}
+
+func TestEW_MatchesNaive(t *testing.T) {
+	for _, decay := range []Decay{DecayAlpha(0.1), DecaySpan(9), DecayHalfLife(5), DecayAlpha(1)} {
+		r := rand.New(rand.NewSource(1))
+		m, v, c := NewEWMA(decay, 0), NewEWVariance(decay, 0), NewEWCovariance(decay, 0)
+		var weights, xs, ys []float64
+		for i := 0; i < 200; i++ {
+			x := r.NormFloat64() + 100
+			y := 0.5*x + r.NormFloat64()
+			m.Add(x)
+			v.Add(x)
+			c.Add(x, y)
+			for j := range weights {
+				weights[j] *= 1 - decay.alpha
+			}
+			weights, xs, ys = append(weights, 1), append(xs, x), append(ys, y)
+			if decay.alpha == 1 {
+				if m.Value() != x || v.Variance() != 0 {
+					t.Fatalf("Expected alpha 1 to keep only the last value, got %v %v", m.Value(), v.Variance())
+				}
+				continue
+			}
+			assertEW(t, i, weights, xs, ys, v, c, m)
+		}
+	}
+}
+
+func TestEW_Decays(t *testing.T) {
+	if a := DecaySpan(9).alpha; a != 0.2 {
+		t.Fatalf("DecaySpan(9) want alpha 0.2 got %v", a)
+	}
+	// after halfLife observations, the weight of the first one is halved
+	if a := DecayHalfLife(4).alpha; math.Abs(math.Pow(1-a, 4)-0.5) > 1e-12 {
+		t.Fatalf("DecayHalfLife(4) gives alpha %v", a)
+	}
+	for _, decay := range []Decay{{}, DecayAlpha(0), DecayAlpha(1.5), DecayTimeHalfLife(-time.Second)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("Expected a panic for %+v", decay)
+				}
+			}()
+			NewEWMA(decay, 0)
+		}()
+	}
+}
+
+func TestEW_TimeHalfLife(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	const halfLife = 10 * time.Second
+	decay := DecayTimeHalfLife(halfLife)
+	m := NewEWMAWithClock(decay, 0, clock)
+	v := NewEWVarianceWithClock(decay, 0, clock)
+	c := NewEWCovarianceWithClock(decay, 0, clock)
+
+	r := rand.New(rand.NewSource(2))
+	var times []time.Time
+	var xs, ys []float64
+	latest := start
+	for i := 0; i < 100; i++ {
+		tm := clock.Advance(time.Duration(r.Intn(5000)) * time.Millisecond) // some steps are 0
+		x, y := r.NormFloat64(), r.NormFloat64()
+		if i%10 == 9 {
+			tm = latest.Add(-3 * time.Second) // out of order, taken as the latest
+			m.AddAt(x, tm)
+			v.AddAt(x, tm)
+			c.AddAt(x, y, tm)
+			tm = latest
+		} else {
+			m.Add(x)
+			v.Add(x)
+			c.Add(x, y)
+		}
+		if tm.After(latest) {
+			latest = tm
+		}
+		times, xs, ys = append(times, tm), append(xs, x), append(ys, y)
+
+		weights := make([]float64, len(times))
+		for j, tj := range times {
+			weights[j] = math.Exp2(-float64(latest.Sub(tj)) / float64(halfLife))
+		}
+		assertEW(t, i, weights, xs, ys, v, c, m)
+	}
+
+	// a regular series with a time half-life matches the same half-life in observations
+	m = NewEWMAWithClock(decay, 0, clock)
+	n := NewEWMA(DecayHalfLife(10), 0)
+	for i := 0; i < 50; i++ {
+		x := r.NormFloat64()
+		clock.Advance(time.Second)
+		m.Add(x)
+		n.Add(x)
+	}
+	if math.Abs(m.Value()-n.Value()) > 1e-12 {
+		t.Fatalf("Time half-life %v differs from half-life %v", m.Value(), n.Value())
+	}
+}
+
+func TestEW_WarmUp(t *testing.T) {
+	m := NewEWMA(DecaySpan(3), 3)
+	if !math.IsNaN(m.Value()) || m.Filled() {
+		t.Fatal("Expected NaN and not Filled without values")
+	}
+	for i := 1; i <= 3; i++ {
+		m.Add(float64(i))
+		if m.Filled() != (i == 3) || m.Count() != i {
+			t.Fatalf("Filled %v after %d values", m.Filled(), i)
+		}
+	}
+	m.Clear()
+	if m.Filled() || m.Count() != 0 || !math.IsNaN(m.Value()) {
+		t.Fatal("Expected an empty EWMA after Clear")
+	}
+
+	c := NewEWCovariance(DecayAlpha(0.5), 2)
+	c.Add(1, 2)
+	c.Add(2, 4)
+	if !c.Filled() || c.Beta() != 2 || c.Correlation() != 1 {
+		t.Fatalf("Unexpected beta %v correlation %v", c.Beta(), c.Correlation())
+	}
+	c.Add(2, 1)
+	c.Clear()
+	if c.Filled() || !math.IsNaN(c.Covariance()) || !math.IsNaN(c.Beta()) {
+		t.Fatal("Expected an empty EWCovariance after Clear")
+	}
+}
+
+func TestEWZScore(t *testing.T) {
+	z := NewEWZScore(DecayAlpha(0.1), 3)
+	if s := z.Add(10); !math.IsNaN(s) || z.Filled() {
+		t.Fatalf("The first value has no score, got %v", s)
+	}
+	if s := z.Add(10); !math.IsNaN(s) {
+		t.Fatalf("Expected NaN against a zero standard deviation, got %v", s)
+	}
+	z.Add(12)
+	if z.Filled() {
+		t.Fatal("Expected not Filled before warm-up")
+	}
+	stats := *z.Stats()
+	s := z.Add(20)
+	if want := (20 - stats.Mean()) / stats.StdDev(); !z.Filled() || s != want || z.Value() != want {
+		t.Fatalf("score got %v want %v", s, want)
+	}
+	if z.Count() != 4 {
+		t.Fatalf("Count want 4 got %d", z.Count())
+	}
+	z.Clear()
+	if z.Filled() || z.Count() != 0 || !math.IsNaN(z.Value()) {
+		t.Fatal("Expected an empty EWZScore after Clear")
+	}
+}
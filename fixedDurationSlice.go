@@ -1,35 +1,120 @@
 package quantainer
 
-import "time"
+import (
+	"slices"
+	"sort"
+	"time"
+)
 
+// OutOfOrderPolicy decides what FixedDurationSlice.AddAt does with a timestamp earlier than the latest one.
+type OutOfOrderPolicy int
+
+const (
+	// OutOfOrderInsert inserts the element at its place in time order, in O(n). This is the default.
+	OutOfOrderInsert OutOfOrderPolicy = iota
+	// OutOfOrderReject drops the element, and AddAt returns false.
+	OutOfOrderReject
+	// OutOfOrderClamp stores the element with the latest timestamp instead of its own.
+	OutOfOrderClamp
+)
+
+// FixedDurationSlice keeps the elements added within the last maxDuration, in time order.
 type FixedDurationSlice[T any] struct {
 	l           []T
 	t           []int64
 	maxDuration int64
+	clock       Clock
+	outOfOrder  OutOfOrderPolicy
+	onEvict     func(T) // called with every element dropped because it expired
 }
 
 func NewFixedDurationSlice[T any](maxDuration time.Duration) *FixedDurationSlice[T] {
+	return NewFixedDurationSliceWithClock[T](maxDuration, SystemClock)
+}
+
+// NewFixedDurationSliceWithClock creates a FixedDurationSlice whose Add timestamps elements with clock.
+func NewFixedDurationSliceWithClock[T any](maxDuration time.Duration, clock Clock) *FixedDurationSlice[T] {
 	return &FixedDurationSlice[T]{
 		maxDuration: maxDuration.Nanoseconds(),
+		clock:       clock,
 	}
 }
 
+// SetOutOfOrderPolicy sets what AddAt does with timestamps earlier than the latest one.
+func (me *FixedDurationSlice[T]) SetOutOfOrderPolicy(policy OutOfOrderPolicy) {
+	me.outOfOrder = policy
+}
+
+// Add adds v with the current time of the slice's clock, and drops the elements older than maxDuration.
 func (me *FixedDurationSlice[T]) Add(v T) {
-	now := time.Now().UnixNano()
+	me.AddAt(v, me.now())
+}
+
+func (me *FixedDurationSlice[T]) now() time.Time {
+	if me.clock == nil {
+		return time.Now()
+	}
+	return me.clock.Now()
+}
+
+// AddAt adds v with timestamp tm, and drops the elements older than maxDuration before the latest timestamp.
+// A timestamp earlier than the latest one is handled according to the OutOfOrderPolicy.
+// It returns false if v was not stored, because it was rejected or is already older than maxDuration.
+func (me *FixedDurationSlice[T]) AddAt(v T, tm time.Time) bool {
+	t := tm.UnixNano()
+	n := len(me.t)
+	latest := t
+	if n > 0 && t < me.t[n-1] {
+		latest = me.t[n-1]
+		switch me.outOfOrder {
+		case OutOfOrderReject:
+			return false
+		case OutOfOrderClamp:
+			t = latest
+		default:
+			if t < latest-me.maxDuration {
+				return false
+			}
+			i := sort.Search(n, func(i int) bool { return me.t[i] > t })
+			me.l = slices.Insert(me.l, i, v)
+			me.t = slices.Insert(me.t, i, t)
+			return true
+		}
+	}
 	me.l = append(me.l, v)
-	me.t = append(me.t, now)
-	cutoff := now - me.maxDuration
+	me.t = append(me.t, t)
+	me.expire(latest - me.maxDuration)
+	return true
+}
+
+// Expire drops the elements older than maxDuration at now, without adding one.
+func (me *FixedDurationSlice[T]) Expire(now time.Time) {
+	me.expire(now.UnixNano() - me.maxDuration)
+}
+
+func (me *FixedDurationSlice[T]) expire(cutoff int64) {
 	i := 0
 	l := len(me.t)
 	for i < l && me.t[i] < cutoff {
 		i++
 	}
 	if i > 0 {
+		if me.onEvict != nil {
+			for _, v := range me.l[:i] {
+				me.onEvict(v)
+			}
+		}
 		me.l = me.l[i:]
 		me.t = me.t[i:]
 	}
 }
 
+// OnEvict sets fn to be called with every element Add, AddAt or Expire drop because it is older than maxDuration.
+// Elements AddAt does not store are not passed to fn.
+func (me *FixedDurationSlice[T]) OnEvict(fn func(v T)) {
+	me.onEvict = fn
+}
+
 func (me *FixedDurationSlice[T]) Head() (t T, tm time.Time, ok bool) {
 	if len(me.l) == 0 {
 		return
@@ -2,6 +2,7 @@ package quantainer
 
 import (
 	"fmt"
+	"testing"
 	"time"
 )
 
@@ -19,3 +20,89 @@ func ExampleFixedDurationSlice_Add() {
 	// 2
 	// 3
 }
+
+func TestFixedDurationSlice_OnEvict(t *testing.T) {
+	l := NewFixedDurationSlice[int](20 * time.Millisecond)
+	var evicted []int
+	l.OnEvict(func(v int) { evicted = append(evicted, v) })
+
+	l.Add(1)
+	l.Add(2)
+	time.Sleep(30 * time.Millisecond)
+	l.Add(3)
+	if fmt.Sprint(evicted) != "[1 2]" || fmt.Sprint(l.Values()) != "[3]" {
+		t.Fatalf("evicted %v, kept %v", evicted, l.Values())
+	}
+}
+
+func TestFixedDurationSlice_Clock(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	l := NewFixedDurationSliceWithClock[int](2*time.Second, clock)
+	var evicted []int
+	l.OnEvict(func(v int) { evicted = append(evicted, v) })
+
+	l.Add(1)
+	clock.Advance(time.Second)
+	l.Add(2)
+	clock.Advance(time.Second)
+	l.Add(3)
+	if fmt.Sprint(l.Values()) != "[1 2 3]" {
+		t.Fatalf("want [1 2 3] got %v", l.Values())
+	}
+	clock.Advance(time.Second)
+	l.Add(4)
+	if fmt.Sprint(l.Values()) != "[2 3 4]" {
+		t.Fatalf("want [2 3 4] got %v", l.Values())
+	}
+	if v, tm, ok := l.Head(); !ok || v != 2 || !tm.Equal(start.Add(time.Second)) {
+		t.Fatalf("Head want 2 at %v got %d at %v (ok=%v)", start.Add(time.Second), v, tm, ok)
+	}
+
+	l.Expire(start.Add(6 * time.Second))
+	if fmt.Sprint(l.Values()) != "[]" || fmt.Sprint(evicted) != "[1 2 3 4]" {
+		t.Fatalf("after Expire kept %v, evicted %v", l.Values(), evicted)
+	}
+}
+
+func TestFixedDurationSlice_OutOfOrder(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+	timestamps := func(l *FixedDurationSlice[int]) (result []int) {
+		for _, t := range l.t {
+			result = append(result, int(time.Unix(0, t).Sub(start)/time.Second))
+		}
+		return
+	}
+
+	insert := NewFixedDurationSlice[int](3 * time.Second)
+	for _, s := range []int{1, 4, 3, 4, 2, 0} {
+		insert.AddAt(s, at(s))
+	}
+	if fmt.Sprint(insert.Values()) != "[1 2 3 4 4]" || fmt.Sprint(timestamps(insert)) != "[1 2 3 4 4]" {
+		t.Fatalf("insert kept %v at %v", insert.Values(), timestamps(insert))
+	}
+	if insert.AddAt(-1, at(0)) {
+		t.Fatal("Expected an element older than maxDuration not to be stored")
+	}
+
+	reject := NewFixedDurationSlice[int](3 * time.Second)
+	reject.SetOutOfOrderPolicy(OutOfOrderReject)
+	for _, s := range []int{1, 3, 2, 3} {
+		if ok := reject.AddAt(s, at(s)); ok != (s != 2) {
+			t.Fatalf("AddAt(%d) returned %v", s, ok)
+		}
+	}
+	if fmt.Sprint(reject.Values()) != "[1 3 3]" {
+		t.Fatalf("reject kept %v", reject.Values())
+	}
+
+	clamp := NewFixedDurationSlice[int](3 * time.Second)
+	clamp.SetOutOfOrderPolicy(OutOfOrderClamp)
+	for _, s := range []int{1, 3, 2, 5} {
+		clamp.AddAt(s, at(s))
+	}
+	if fmt.Sprint(clamp.Values()) != "[3 2 5]" || fmt.Sprint(timestamps(clamp)) != "[3 3 5]" {
+		t.Fatalf("clamp kept %v at %v", clamp.Values(), timestamps(clamp))
+	}
+}
@@ -0,0 +1,148 @@
+package quantainer
+
+import (
+	"math"
+	"time"
+)
+
+// FixedDurationStats is a FixedDurationSlice of numbers that maintains their sum, count, mean, min and max
+// as elements enter and expire: sum, count and mean in O(1), min and max in O(log n) per element.
+// The readers expire the elements older than maxDuration at the current time of the clock first, so the
+// aggregates keep up with the clock when elements stop arriving. With timestamps that do not follow the
+// system clock, e.g. when replaying, use a clock that does, such as a ManualClock.
+// Elements must not be NaN.
+type FixedDurationStats[T Number] struct {
+	s       FixedDurationSlice[T]
+	sum     kahanSum
+	m       orderStatTree[T]
+	onEvict func(T)
+}
+
+func NewFixedDurationStats[T Number](maxDuration time.Duration) *FixedDurationStats[T] {
+	return NewFixedDurationStatsWithClock[T](maxDuration, SystemClock)
+}
+
+// NewFixedDurationStatsWithClock creates a FixedDurationStats whose Add timestamps elements with clock.
+func NewFixedDurationStatsWithClock[T Number](maxDuration time.Duration, clock Clock) *FixedDurationStats[T] {
+	result := &FixedDurationStats[T]{
+		s: *NewFixedDurationSliceWithClock[T](maxDuration, clock),
+	}
+	result.s.OnEvict(result.evict)
+	return result
+}
+
+// evict removes an element expired from s from the aggregates.
+func (me *FixedDurationStats[T]) evict(v T) {
+	me.m.Remove(v)
+	if me.m.Len() == 0 {
+		me.sum.reset()
+	} else {
+		me.sum.add(-float64(v))
+	}
+	if me.onEvict != nil {
+		me.onEvict(v)
+	}
+}
+
+// SetOutOfOrderPolicy sets what AddAt does with timestamps earlier than the latest one.
+func (me *FixedDurationStats[T]) SetOutOfOrderPolicy(policy OutOfOrderPolicy) {
+	me.s.SetOutOfOrderPolicy(policy)
+}
+
+// OnEvict sets fn to be called with every element that expires, like FixedDurationSlice.OnEvict.
+func (me *FixedDurationStats[T]) OnEvict(fn func(v T)) {
+	me.onEvict = fn
+}
+
+// Add adds v with the current time of the clock, and expires the elements older than maxDuration.
+func (me *FixedDurationStats[T]) Add(v T) {
+	me.AddAt(v, me.s.now())
+}
+
+// AddAt adds v with timestamp tm like FixedDurationSlice.AddAt, and returns false if v was not stored.
+func (me *FixedDurationStats[T]) AddAt(v T, tm time.Time) bool {
+	if !me.s.AddAt(v, tm) {
+		return false
+	}
+	me.sum.add(float64(v))
+	me.m.Add(v)
+	return true
+}
+
+// Expire drops the elements older than maxDuration at now, without adding one.
+func (me *FixedDurationStats[T]) Expire(now time.Time) {
+	me.s.Expire(now)
+}
+
+// expire drops the elements older than maxDuration at the current time of the clock.
+func (me *FixedDurationStats[T]) expire() {
+	me.s.Expire(me.s.now())
+}
+
+// Sum returns the sum of the elements in the window.
+func (me *FixedDurationStats[T]) Sum() float64 {
+	me.expire()
+	return me.sum.sum
+}
+
+func (me *FixedDurationStats[T]) Count() int {
+	me.expire()
+	return me.m.Len()
+}
+
+// Mean returns the arithmetic mean, or NaN if the window is empty.
+func (me *FixedDurationStats[T]) Mean() float64 {
+	me.expire()
+	if me.m.Len() == 0 {
+		return math.NaN()
+	}
+	return me.sum.sum / float64(me.m.Len())
+}
+
+// Min returns the smallest element. ok is false if the window is empty.
+func (me *FixedDurationStats[T]) Min() (result T, ok bool) {
+	me.expire()
+	return me.m.Select(0)
+}
+
+// Max returns the largest element. ok is false if the window is empty.
+func (me *FixedDurationStats[T]) Max() (result T, ok bool) {
+	me.expire()
+	return me.m.Select(me.m.Len() - 1)
+}
+
+// Rate returns the number of elements per second over maxDuration, or NaN if maxDuration is 0.
+func (me *FixedDurationStats[T]) Rate() float64 {
+	me.expire()
+	return me.perSecond(float64(me.m.Len()))
+}
+
+// SumRate returns the sum of the elements per second over maxDuration, e.g. the traded volume per second,
+// or NaN if maxDuration is 0.
+func (me *FixedDurationStats[T]) SumRate() float64 {
+	me.expire()
+	return me.perSecond(me.sum.sum)
+}
+
+func (me *FixedDurationStats[T]) perSecond(v float64) float64 {
+	if me.s.maxDuration == 0 {
+		return math.NaN()
+	}
+	return v / time.Duration(me.s.maxDuration).Seconds()
+}
+
+func (me *FixedDurationStats[T]) Head() (t T, tm time.Time, ok bool) {
+	me.expire()
+	return me.s.Head()
+}
+
+func (me *FixedDurationStats[T]) Values() []T {
+	me.expire()
+	return me.s.Values()
+}
+
+func (me *FixedDurationStats[T]) Clear() {
+	me.s.Clear()
+	me.sum.reset()
+	me.m.Clear()
+}
@@ -0,0 +1,117 @@
+package quantainer
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func ExampleFixedDurationStats_Sum() {
+	clock := NewManualClock(time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC))
+	volume := NewFixedDurationStatsWithClock[float64](5*time.Second, clock)
+	for _, qty := range []float64{10, 30, 20, 40} {
+		volume.Add(qty)
+		clock.Advance(2 * time.Second)
+	}
+	// the first two trades are older than 5 seconds by now
+	fmt.Println(volume.Sum(), volume.Count(), volume.Mean(), volume.SumRate())
+	// Output:
+	// 60 2 30 12
+}
+
+func TestFixedDurationStats_MinMax(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	s := NewFixedDurationStatsWithClock[int](3*time.Second, clock)
+	if _, ok := s.Min(); ok || !math.IsNaN(s.Mean()) || s.Count() != 0 {
+		t.Fatal("Expected no min and NaN mean in an empty window")
+	}
+
+	var evicted []int
+	s.OnEvict(func(v int) { evicted = append(evicted, v) })
+	for _, v := range []int{5, 1, 9, 3} {
+		s.Add(v)
+		clock.Advance(time.Second)
+	}
+	if min, _ := s.Min(); min != 1 {
+		t.Fatalf("Min want 1 got %d", min)
+	}
+	if max, _ := s.Max(); max != 9 {
+		t.Fatalf("Max want 9 got %d", max)
+	}
+	// 4s: the readers expire 5
+	if v, tm, ok := s.Head(); !ok || v != 1 || !tm.Equal(start.Add(time.Second)) {
+		t.Fatalf("Head want 1 at %v got %d at %v", start.Add(time.Second), v, tm)
+	}
+
+	s.Expire(clock.Advance(time.Second)) // 5s: 1 expires
+	if min, _ := s.Min(); min != 3 || s.Sum() != 12 || fmt.Sprint(s.Values()) != "[9 3]" || fmt.Sprint(evicted) != "[5 1]" {
+		t.Fatalf("after Expire min=%d sum=%v values=%v evicted=%v", min, s.Sum(), s.Values(), evicted)
+	}
+	if rate := s.Rate(); rate != 2.0/3 {
+		t.Fatalf("Rate want 2/3 got %v", rate)
+	}
+
+	s.Clear()
+	if _, ok := s.Max(); ok || s.Sum() != 0 || len(s.Values()) != 0 {
+		t.Fatal("Expected an empty window after Clear")
+	}
+}
+
+func TestFixedDurationStats_ExpiresWithClock(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC))
+	s := NewFixedDurationStatsWithClock[float64](5*time.Second, clock)
+	for _, v := range []float64{1, 2, 3} {
+		s.Add(v)
+	}
+	if s.Rate() != 0.6 {
+		t.Fatalf("Rate want 0.6 got %v", s.Rate())
+	}
+
+	// no more elements arrive
+	clock.Advance(6 * time.Second)
+	if _, ok := s.Max(); ok || s.Rate() != 0 || s.SumRate() != 0 || s.Sum() != 0 || s.Count() != 0 || !math.IsNaN(s.Mean()) {
+		t.Fatalf("Expected an empty window once the clock passed it, got rate %v sum %v", s.Rate(), s.Sum())
+	}
+}
+
+// Random out-of-order timestamps, checked against recomputing from Values.
+func TestFixedDurationStats_Random(t *testing.T) {
+	for _, policy := range []OutOfOrderPolicy{OutOfOrderInsert, OutOfOrderReject, OutOfOrderClamp} {
+		rnd := rand.New(rand.NewSource(int64(policy)))
+		start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+		clock := NewManualClock(start)
+		s := NewFixedDurationStatsWithClock[float64](time.Second, clock)
+		s.SetOutOfOrderPolicy(policy)
+		now := start
+		for step := 0; step < 2000; step++ {
+			now = clock.Advance(time.Duration(rnd.Intn(100)) * time.Millisecond)
+			tm := now.Add(-time.Duration(rnd.Intn(300)) * time.Millisecond)
+			s.AddAt(math.Round(rnd.NormFloat64()*100)/4, tm)
+			if step%50 == 0 {
+				s.Expire(now.Add(500 * time.Millisecond))
+			}
+
+			values := s.Values()
+			if len(values) != s.Count() {
+				t.Fatalf("policy %d step %d: Count %d, %d values", policy, step, s.Count(), len(values))
+			}
+			if len(values) == 0 {
+				continue
+			}
+			sum, min, max := 0.0, math.Inf(1), math.Inf(-1)
+			for _, v := range values {
+				sum += v
+				min = math.Min(min, v)
+				max = math.Max(max, v)
+			}
+			gotMin, _ := s.Min()
+			gotMax, _ := s.Max()
+			if math.Abs(s.Sum()-sum) > 1e-9 || gotMin != min || gotMax != max || math.Abs(s.Mean()-sum/float64(len(values))) > 1e-9 {
+				t.Fatalf("policy %d step %d: sum %v/%v min %v/%v max %v/%v", policy, step, s.Sum(), sum, gotMin, min, gotMax, max)
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@ package quantainer
 type FixedList[T any] struct {
 	l       List[T]
 	maxSize func() int
+	onEvict func(T) // called with every element dropped by AddFirst and AddLast
 }
 
 func NewFixedList[T any](size int) *FixedList[T] {
@@ -22,17 +23,29 @@ func NewFixedListConfigurable[T any](configLoader func() int) *FixedList[T] {
 func (me *FixedList[T]) AddFirst(v T) {
 	me.l.AddFirst(v)
 	for me.l.count > me.maxSize() {
-		me.l.PopLast()
+		me.evict(me.l.PopLast())
 	}
 }
 
 func (me *FixedList[T]) AddLast(v T) {
 	me.l.AddLast(v)
 	for me.l.count > me.maxSize() {
-		me.l.PopFirst()
+		me.evict(me.l.PopFirst())
 	}
 }
 
+func (me *FixedList[T]) evict(n *Node[T]) {
+	if me.onEvict != nil {
+		me.onEvict(n.Value)
+	}
+}
+
+// OnEvict sets fn to be called with every element AddFirst and AddLast drop from the other end of the list
+// to stay within maxSize, including the added element itself if maxSize is 0.
+func (me *FixedList[T]) OnEvict(fn func(v T)) {
+	me.onEvict = fn
+}
+
 func (me *FixedList[T]) Remove(node *Node[T]) (next *Node[T]) {
 	return me.l.Remove(node)
 }
@@ -58,3 +58,24 @@ func TestFixedList_Filled(t *testing.T) {
 		t.Fatalf("at capacity Filled want true")
 	}
 }
+
+func TestFixedList_OnEvict(t *testing.T) {
+	size := 3
+	l := NewFixedListConfigurable[int](func() int { return size })
+	var evicted []int
+	l.OnEvict(func(v int) { evicted = append(evicted, v) })
+
+	for i := 1; i <= 4; i++ {
+		l.AddLast(i)
+	}
+	l.AddFirst(0)
+	if fmt.Sprint(evicted) != "[1 4]" {
+		t.Fatalf("evicted want [1 4] got %v", evicted)
+	}
+
+	size = 1
+	l.AddLast(5)
+	if fmt.Sprint(evicted) != "[1 4 0 2 3]" || fmt.Sprint(l.ToSlice()) != "[5]" {
+		t.Fatalf("after shrinking evicted %v, kept %v", evicted, l.ToSlice())
+	}
+}
@@ -0,0 +1,106 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/szmcdull/quantainer"
+)
+
+// ADX is Wilder's average directional index over period bars, in [0, 100], with the +DI and -DI lines.
+type ADX struct {
+	period          int
+	n               int // number of bars added
+	prev            quantainer.Bar
+	tr, plus, minus float64 // Wilder-smoothed sums of the true range and of the directional movements
+	dxN             int     // number of DX values added to adx
+	adx             float64
+}
+
+func NewADX(period int) *ADX {
+	return &ADX{
+		period: period,
+	}
+}
+
+func (me *ADX) AddBar(bar quantainer.Bar) {
+	me.n++
+	prev := me.prev
+	me.prev = bar
+	if me.n == 1 {
+		return
+	}
+
+	up, down := bar.High-prev.High, prev.Low-bar.Low
+	var plusDM, minusDM float64
+	if up > down && up > 0 {
+		plusDM = up
+	}
+	if down > up && down > 0 {
+		minusDM = down
+	}
+	tr := trueRange(bar, prev.Close)
+
+	p := float64(me.period)
+	if me.n-1 <= me.period {
+		me.tr += tr
+		me.plus += plusDM
+		me.minus += minusDM
+		if me.n-1 < me.period {
+			return
+		}
+	} else {
+		me.tr += tr - me.tr/p
+		me.plus += plusDM - me.plus/p
+		me.minus += minusDM - me.minus/p
+	}
+
+	dx := 0.0
+	if plusDI, minusDI := me.PlusDI(), me.MinusDI(); plusDI+minusDI > 0 {
+		dx = 100 * math.Abs(plusDI-minusDI) / (plusDI + minusDI)
+	}
+	if me.dxN < me.period {
+		me.dxN++
+		me.adx += dx
+		if me.dxN == me.period {
+			me.adx /= p
+		}
+		return
+	}
+	me.adx = (me.adx*(p-1) + dx) / p
+}
+
+// Ready returns true once the ADX is available, after 2*period bars.
+func (me *ADX) Ready() bool {
+	return me.period > 0 && me.dxN == me.period
+}
+
+func (me *ADX) Value() float64 {
+	if !me.Ready() {
+		return math.NaN()
+	}
+	return me.adx
+}
+
+// PlusDI returns the +DI line, which is available after period+1 bars.
+func (me *ADX) PlusDI() float64 {
+	return me.di(me.plus)
+}
+
+// MinusDI returns the -DI line, which is available after period+1 bars.
+func (me *ADX) MinusDI() float64 {
+	return me.di(me.minus)
+}
+
+func (me *ADX) di(dm float64) float64 {
+	if me.n <= me.period {
+		return math.NaN()
+	}
+	if me.tr == 0 {
+		return 0
+	}
+	return 100 * dm / me.tr
+}
+
+func (me *ADX) Clear() {
+	*me = ADX{period: me.period}
+}
@@ -0,0 +1,60 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/szmcdull/quantainer"
+)
+
+// TA-Lib starts Wilder's sums of the true range and of the directional movements from period-1 bars,
+// where Wilder sums period bars, so its values differ from these, which were computed with
+// a straightforward implementation of Wilder's definitions.
+func TestADX(t *testing.T) {
+	nan := math.NaN()
+	adx := NewADX(14)
+	runIndicatorCases(t, []indicatorCase{
+		{"ADX", func(i int) { adx.AddBar(bar(i)) }, adx.Ready, []indicatorOutput{
+			{"Value", adx.Value, map[int]float64{
+				14: nan, 25: nan, 27: 26.5900076106, 30: 22.4994947189, 33: 21.2194140784, 39: 19.424419206,
+			}},
+			{"PlusDI", adx.PlusDI, map[int]float64{
+				13: nan, 14: 24.9203539823, 15: 25.2388881773, 19: 35.3457484251, 25: 40.8585325203,
+				27: 33.9941016417, 30: 28.0254685086, 33: 22.7328732228, 39: 38.1462310265,
+			}},
+			{"MinusDI", adx.MinusDI, map[int]float64{
+				13: nan, 14: 31.9646017699, 15: 30.0730114, 19: 22.0811566674, 25: 14.3211944723,
+				27: 23.5164391481, 30: 29.136994825, 33: 32.8828981059, 39: 20.1900068615,
+			}},
+		}},
+	})
+}
+
+// After period flat bars both ways of starting the sums give 0, so the series agrees with TA-Lib exactly.
+func TestADX_FlatStart(t *testing.T) {
+	nan := math.NaN()
+	adx := NewADX(14)
+	add := func(i int) {
+		if i == 0 {
+			for j := 0; j < 14; j++ {
+				adx.AddBar(quantainer.Bar{High: closes[0], Low: closes[0], Close: closes[0]})
+			}
+		}
+		adx.AddBar(bar(i))
+	}
+	runIndicatorCases(t, []indicatorCase{
+		{"ADX", add, adx.Ready, []indicatorOutput{
+			{"Value", adx.Value, map[int]float64{
+				12: nan, 13: 59.8186018933, 14: 57.6740422162, 19: 44.6330914040, 25: 46.1829549136,
+				30: 36.0570131897, 39: 26.9319336178,
+			}},
+			{"PlusDI", adx.PlusDI, map[int]float64{
+				0: 55.5555555556, 1: 64.8009232545, 9: 31.4628444042, 13: 18.8348696709, 19: 35.3084839967,
+				30: 27.1340969328, 39: 38.2970392004,
+			}},
+			{"MinusDI", adx.MinusDI, map[int]float64{
+				0: 0, 9: 28.4765752452, 13: 40.0034399981, 19: 21.1402455593, 30: 29.6265962875, 39: 19.9167271213,
+			}},
+		}},
+	})
+}
@@ -0,0 +1,137 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/szmcdull/quantainer"
+)
+
+type (
+	// SMA is the simple moving average of the last period values.
+	SMA struct {
+		rs quantainer.RollingStats[float64]
+	}
+
+	// EMA is the exponential moving average with alpha = 2 / (period + 1),
+	// seeded with the simple average of the first period values.
+	EMA struct {
+		period int
+		alpha  float64
+		n      int
+		sum    float64 // sum of the first period values
+		value  float64
+	}
+
+	// WMA is the linearly weighted moving average of the last period values,
+	// the newest weighing period and the oldest 1.
+	WMA struct {
+		period   int
+		rb       quantainer.RingBuffer[float64]
+		sum      float64 // sum of the values in the window
+		weighted float64 // sum of the values times their weights
+	}
+)
+
+func NewSMA(period int) *SMA {
+	return &SMA{
+		rs: *quantainer.NewRollingStats[float64](period),
+	}
+}
+
+func (me *SMA) Add(v float64) {
+	me.rs.AddLast(v)
+}
+
+// Ready returns true once period values were added.
+func (me *SMA) Ready() bool {
+	return me.rs.Filled()
+}
+
+func (me *SMA) Value() float64 {
+	if !me.Ready() {
+		return math.NaN()
+	}
+	return me.rs.Mean()
+}
+
+func (me *SMA) Clear() {
+	me.rs.Clear()
+}
+
+func NewEMA(period int) *EMA {
+	return &EMA{
+		period: period,
+		alpha:  2 / float64(period+1),
+	}
+}
+
+func (me *EMA) Add(v float64) {
+	if me.n < me.period {
+		me.n++
+		me.sum += v
+		if me.n == me.period {
+			me.value = me.sum / float64(me.period)
+		}
+		return
+	}
+	me.value += me.alpha * (v - me.value)
+}
+
+// Ready returns true once period values were added.
+func (me *EMA) Ready() bool {
+	return me.period > 0 && me.n == me.period
+}
+
+func (me *EMA) Value() float64 {
+	if !me.Ready() {
+		return math.NaN()
+	}
+	return me.value
+}
+
+func (me *EMA) Clear() {
+	*me = EMA{period: me.period, alpha: me.alpha}
+}
+
+func NewWMA(period int) *WMA {
+	return &WMA{
+		period: period,
+		rb:     quantainer.NewRingBuffer[float64](period),
+	}
+}
+
+// Add adds a value in O(1): the weights of the older values all drop by one,
+// which subtracts their sum from the weighted sum.
+func (me *WMA) Add(v float64) {
+	if me.period <= 0 {
+		return
+	}
+	if me.rb.Full() {
+		me.weighted -= me.sum
+		me.sum -= *me.rb.First()
+		me.weighted += float64(me.period) * v
+	} else {
+		me.weighted += float64(me.rb.Count()+1) * v
+	}
+	me.sum += v
+	me.rb.AddLast(v)
+}
+
+// Ready returns true once period values were added.
+func (me *WMA) Ready() bool {
+	return me.rb.Filled()
+}
+
+func (me *WMA) Value() float64 {
+	if !me.Ready() {
+		return math.NaN()
+	}
+	n := float64(me.rb.Count())
+	return me.weighted / (n * (n + 1) / 2)
+}
+
+func (me *WMA) Clear() {
+	me.rb.Clear()
+	me.sum = 0
+	me.weighted = 0
+}
@@ -0,0 +1,54 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func ExampleSMA() {
+	sma := NewSMA(3)
+	for _, v := range []float64{1, 2, 3, 4} {
+		sma.Add(v)
+		fmt.Println(sma.Ready(), sma.Value())
+	}
+	// Output:
+	// false NaN
+	// false NaN
+	// true 2
+	// true 3
+}
+
+func TestAverages(t *testing.T) {
+	nan := math.NaN()
+	sma, ema, wma := NewSMA(5), NewEMA(5), NewWMA(5)
+	runIndicatorCases(t, []indicatorCase{
+		{"SMA", func(i int) { sma.Add(closes[i]) }, sma.Ready, []indicatorOutput{
+			{"Value", sma.Value, map[int]float64{
+				3: nan, 4: 103.18, 5: 104.376, 9: 104.65, 13: 99.924, 14: 99.044, 15: 98.476,
+				19: 100.84, 25: 109.004, 27: 108.98, 30: 106.028, 33: 102.642, 39: 106.39,
+			}},
+		}},
+		{"EMA", func(i int) { ema.Add(closes[i]) }, ema.Ready, []indicatorOutput{
+			{"Value", ema.Value, map[int]float64{
+				3: nan, 4: 103.18, 5: 104.3466666667, 9: 103.726872428, 13: 99.7286414673, 14: 99.0890943115,
+				15: 99.0293962077, 19: 101.4923498682, 25: 108.6664888773, 27: 108.158439501, 30: 105.5973154077,
+				33: 102.7532786393, 39: 106.9098077269,
+			}},
+		}},
+		{"WMA", func(i int) { wma.Add(closes[i]) }, wma.Ready, []indicatorOutput{
+			{"Value", wma.Value, map[int]float64{
+				3: nan, 4: 104.0393333333, 5: 105.206, 9: 103.93, 13: 99.1073333333, 14: 98.4026666667,
+				15: 98.358, 19: 101.69, 25: 109.466, 27: 108.6326666667, 30: 105.216, 33: 102.088, 39: 107.4046666667,
+			}},
+		}},
+	})
+}
+
+func TestWMA_ZeroPeriod(t *testing.T) {
+	wma := NewWMA(0)
+	wma.Add(1)
+	if wma.Ready() || !math.IsNaN(wma.Value()) {
+		t.Fatal("Expected a WMA of period 0 never to be ready")
+	}
+}
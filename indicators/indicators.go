@@ -0,0 +1,45 @@
+// Package indicators implements streaming technical indicators on top of the quantainer rolling windows.
+// Each indicator consumes one value or one bar at a time and keeps only the state it needs.
+// Value returns NaN until Ready returns true.
+package indicators
+
+import (
+	"math"
+
+	"github.com/szmcdull/quantainer"
+)
+
+type (
+	// Indicator is an indicator computed from a series of values, usually closing prices.
+	Indicator interface {
+		Add(v float64)
+		Ready() bool
+		Value() float64
+		Clear()
+	}
+
+	// BarIndicator is an indicator computed from a series of OHLC bars.
+	BarIndicator interface {
+		AddBar(bar quantainer.Bar)
+		Ready() bool
+		Value() float64
+		Clear()
+	}
+)
+
+var (
+	_ Indicator    = (*SMA)(nil)
+	_ Indicator    = (*EMA)(nil)
+	_ Indicator    = (*WMA)(nil)
+	_ Indicator    = (*RSI)(nil)
+	_ Indicator    = (*MACD)(nil)
+	_ Indicator    = (*Bollinger)(nil)
+	_ BarIndicator = (*ATR)(nil)
+	_ BarIndicator = (*Stochastic)(nil)
+	_ BarIndicator = (*ADX)(nil)
+)
+
+// trueRange returns the true range of bar given the close of the previous bar.
+func trueRange(bar quantainer.Bar, prevClose float64) float64 {
+	return math.Max(bar.High-bar.Low, math.Max(math.Abs(bar.High-prevClose), math.Abs(bar.Low-prevClose)))
+}
@@ -0,0 +1,98 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/szmcdull/quantainer"
+)
+
+// The reference values of the tests were computed from these series by TA-Lib, through its Go port
+// github.com/markcheno/go-talib, except where a test says otherwise.
+var (
+	closes = []float64{100.7, 101.54, 103.49, 104.51, 105.66, 106.68, 105.75, 104.72, 103.89, 102.21, 101.75, 99.39, 98.62, 97.65, 97.81, 98.91, 99.13, 100.21, 102.2, 103.75, 106.57, 107.18, 108.74, 109.21, 109.75, 110.14, 108.64, 107.16, 106.06, 104.3, 103.98, 101.96, 101.67, 101.3, 102.08, 103.78, 104.52, 105.95, 108.1, 109.6}
+	highs  = []float64{101.2, 102.34, 104.59, 105.01, 106.46, 107.78, 106.25, 105.52, 104.99, 102.71, 102.55, 100.49, 99.12, 98.45, 98.91, 99.41, 99.93, 101.31, 102.7, 104.55, 107.67, 107.68, 109.54, 110.31, 110.25, 110.94, 109.74, 107.66, 106.86, 105.4, 104.48, 102.76, 102.77, 101.8, 102.88, 104.88, 105.02, 106.75, 109.2, 110.1}
+	lows   = []float64{100.3, 100.89, 102.59, 103.36, 105.26, 106.03, 104.85, 103.57, 103.49, 101.56, 100.85, 98.24, 98.22, 97.0, 96.91, 97.76, 98.73, 99.56, 101.3, 102.6, 106.17, 106.53, 107.84, 108.06, 109.35, 109.49, 107.74, 106.01, 105.66, 103.65, 103.08, 100.81, 101.27, 100.65, 101.18, 102.63, 104.12, 105.3, 107.2, 108.45}
+)
+
+func bar(i int) quantainer.Bar {
+	return quantainer.Bar{High: highs[i], Low: lows[i], Close: closes[i]}
+}
+
+// indicatorCase adds the samples one at a time and checks the outputs against want after each step.
+// want is NaN where the output is not available.
+type indicatorCase struct {
+	name    string
+	add     func(i int)
+	ready   func() bool // must be true exactly where the first output is available
+	outputs []indicatorOutput
+}
+
+type indicatorOutput struct {
+	name  string
+	value func() float64
+	want  map[int]float64
+}
+
+func runIndicatorCases(t *testing.T, cases []indicatorCase) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := range closes {
+				tc.add(i)
+				for j, out := range tc.outputs {
+					want, ok := out.want[i]
+					if !ok {
+						continue
+					}
+					got := out.value()
+					if math.IsNaN(want) != math.IsNaN(got) || math.Abs(got-want) > 1e-8 {
+						t.Fatalf("%s at %d want %v got %v", out.name, i, want, got)
+					}
+					if j == 0 && tc.ready() == math.IsNaN(want) {
+						t.Fatalf("At %d Ready is %v with value %v", i, tc.ready(), got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestClear(t *testing.T) {
+	for _, ind := range []Indicator{NewSMA(5), NewEMA(5), NewWMA(5), NewRSI(14), NewMACD(12, 26, 9), NewBollinger(20, 2)} {
+		for _, c := range closes {
+			ind.Add(c)
+		}
+		if !ind.Ready() {
+			t.Fatalf("Expected %T to be ready", ind)
+		}
+		want := ind.Value()
+		ind.Clear()
+		if ind.Ready() || !math.IsNaN(ind.Value()) {
+			t.Fatalf("Expected %T not to be ready after Clear", ind)
+		}
+		for _, c := range closes {
+			ind.Add(c)
+		}
+		if got := ind.Value(); got != want {
+			t.Fatalf("%T after Clear want %v got %v", ind, want, got)
+		}
+	}
+
+	for _, ind := range []BarIndicator{NewATR(14), NewStochastic(14, 3), NewADX(14)} {
+		for i := range closes {
+			ind.AddBar(bar(i))
+		}
+		want := ind.Value()
+		ind.Clear()
+		if ind.Ready() || !math.IsNaN(ind.Value()) {
+			t.Fatalf("Expected %T not to be ready after Clear", ind)
+		}
+		for i := range closes {
+			ind.AddBar(bar(i))
+		}
+		if got := ind.Value(); got != want {
+			t.Fatalf("%T after Clear want %v got %v", ind, want, got)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/szmcdull/quantainer"
+)
+
+type (
+	// RSI is Wilder's relative strength index over period changes, in [0, 100].
+	RSI struct {
+		period           int
+		n                int
+		prev             float64
+		avgGain, avgLoss float64
+	}
+
+	// MACD is the difference between a fast and a slow EMA (the MACD line),
+	// with a signal EMA of that difference.
+	MACD struct {
+		fast, slow, signal EMA
+	}
+
+	// Stochastic is the stochastic oscillator: %K is where the close lies in the high-low range
+	// of the last kPeriod bars, in [0, 100], and %D is the SMA of the last dPeriod %K values.
+	Stochastic struct {
+		highs, lows quantainer.RollingMinMax[float64]
+		k           float64
+		d           SMA
+	}
+)
+
+func NewRSI(period int) *RSI {
+	return &RSI{
+		period: period,
+	}
+}
+
+func (me *RSI) Add(v float64) {
+	me.n++
+	if me.n == 1 {
+		me.prev = v
+		return
+	}
+	change := v - me.prev
+	me.prev = v
+	gain, loss := math.Max(change, 0), math.Max(-change, 0)
+	p := float64(me.period)
+	if me.n <= me.period+1 {
+		me.avgGain += gain
+		me.avgLoss += loss
+		if me.n == me.period+1 {
+			me.avgGain /= p
+			me.avgLoss /= p
+		}
+		return
+	}
+	me.avgGain = (me.avgGain*(p-1) + gain) / p
+	me.avgLoss = (me.avgLoss*(p-1) + loss) / p
+}
+
+// Ready returns true once period+1 values were added.
+func (me *RSI) Ready() bool {
+	return me.period > 0 && me.n > me.period
+}
+
+// Value returns the RSI. It is 100 if there was no loss in the window, and 50 if there was no change at all.
+func (me *RSI) Value() float64 {
+	if !me.Ready() {
+		return math.NaN()
+	}
+	if me.avgLoss == 0 {
+		if me.avgGain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+me.avgGain/me.avgLoss)
+}
+
+func (me *RSI) Clear() {
+	*me = RSI{period: me.period}
+}
+
+// NewMACD creates a MACD, usually with fast=12, slow=26 and signal=9.
+func NewMACD(fast, slow, signal int) *MACD {
+	return &MACD{
+		fast:   *NewEMA(fast),
+		slow:   *NewEMA(slow),
+		signal: *NewEMA(signal),
+	}
+}
+
+func (me *MACD) Add(v float64) {
+	me.fast.Add(v)
+	me.slow.Add(v)
+	if me.fast.Ready() && me.slow.Ready() {
+		me.signal.Add(me.Line())
+	}
+}
+
+// Ready returns true once the signal line is available.
+func (me *MACD) Ready() bool {
+	return me.signal.Ready()
+}
+
+// Value returns the MACD line once the signal line is available too.
+func (me *MACD) Value() float64 {
+	if !me.Ready() {
+		return math.NaN()
+	}
+	return me.Line()
+}
+
+// Line returns the MACD line, which is available before the signal line; it is NaN until both EMAs are ready.
+func (me *MACD) Line() float64 {
+	return me.fast.Value() - me.slow.Value()
+}
+
+func (me *MACD) Signal() float64 {
+	return me.signal.Value()
+}
+
+// Histogram returns the MACD line minus the signal line.
+func (me *MACD) Histogram() float64 {
+	return me.Value() - me.Signal()
+}
+
+func (me *MACD) Clear() {
+	me.fast.Clear()
+	me.slow.Clear()
+	me.signal.Clear()
+}
+
+// NewStochastic creates a stochastic oscillator, usually with kPeriod=14 and dPeriod=3.
+func NewStochastic(kPeriod, dPeriod int) *Stochastic {
+	return &Stochastic{
+		highs: *quantainer.NewRollingMinMax[float64](kPeriod),
+		lows:  *quantainer.NewRollingMinMax[float64](kPeriod),
+		k:     math.NaN(),
+		d:     *NewSMA(dPeriod),
+	}
+}
+
+func (me *Stochastic) AddBar(bar quantainer.Bar) {
+	me.highs.AddLast(bar.High)
+	me.lows.AddLast(bar.Low)
+	if !me.highs.Filled() {
+		return
+	}
+	high, low := *me.highs.Max(), *me.lows.Min()
+	me.k = 50
+	if high > low {
+		me.k = 100 * (bar.Close - low) / (high - low)
+	}
+	me.d.Add(me.k)
+}
+
+// Ready returns true once %D is available, after kPeriod+dPeriod-1 bars.
+func (me *Stochastic) Ready() bool {
+	return me.d.Ready()
+}
+
+// Value returns %K once %D is available too.
+func (me *Stochastic) Value() float64 {
+	if !me.Ready() {
+		return math.NaN()
+	}
+	return me.k
+}
+
+// K returns %K, which is available after kPeriod bars; it is 50 if the range is empty.
+func (me *Stochastic) K() float64 {
+	return me.k
+}
+
+// D returns %D.
+func (me *Stochastic) D() float64 {
+	return me.d.Value()
+}
+
+func (me *Stochastic) Clear() {
+	me.highs.Clear()
+	me.lows.Clear()
+	me.k = math.NaN()
+	me.d.Clear()
+}
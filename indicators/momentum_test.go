@@ -0,0 +1,50 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMomentum(t *testing.T) {
+	nan := math.NaN()
+	rsi, macd, stoch := NewRSI(14), NewMACD(12, 26, 9), NewStochastic(14, 3)
+	runIndicatorCases(t, []indicatorCase{
+		{"RSI", func(i int) { rsi.Add(closes[i]) }, rsi.Ready, []indicatorOutput{
+			{"Value", rsi.Value, map[int]float64{
+				13: nan, 14: 40.4746209624, 15: 44.7862283053, 19: 60.5340948632, 25: 73.4853015268,
+				27: 60.181472211, 30: 48.9890178301, 33: 41.1488251461, 39: 66.3128261021,
+			}},
+		}},
+		// TA-Lib's MACD seeds the fast EMA later, to start both EMAs on the same bar,
+		// so these are TA-Lib's EMA(12)-EMA(26) and the EMA(9) of that line.
+		{"MACD", func(i int) { macd.Add(closes[i]) }, macd.Ready, []indicatorOutput{
+			{"Value", macd.Value, map[int]float64{25: nan, 30: nan, 33: 0.4840981596, 39: 1.0306062464}},
+			{"Signal", macd.Signal, map[int]float64{30: nan, 33: 1.7958281684, 39: 0.9132379948}},
+			{"Histogram", macd.Histogram, map[int]float64{30: nan, 33: 0.4840981596 - 1.7958281684, 39: 1.0306062464 - 0.9132379948}},
+		}},
+		{"Stochastic", func(i int) { stoch.AddBar(bar(i)) }, stoch.Ready, []indicatorOutput{
+			{"Value", stoch.Value, map[int]float64{
+				14: nan, 15: 18.3992640294, 19: 73.2334047109, 25: 94.2979330007, 27: 73.0577334284,
+				30: 38.8400702988, 33: 6.3168124393, 39: 94.708994709,
+			}},
+			{"D", stoch.D, map[int]float64{
+				14: nan, 15: 10.9028724813, 19: 50.7527479058, 25: 93.9699577664, 27: 83.654074602,
+				30: 49.1442064185, 33: 8.7196219154, 39: 72.8719000828,
+			}},
+		}},
+	})
+}
+
+func TestRSI_Flat(t *testing.T) {
+	rsi := NewRSI(3)
+	for i := 0; i < 4; i++ {
+		rsi.Add(10)
+	}
+	if v := rsi.Value(); v != 50 {
+		t.Fatalf("RSI without changes want 50 got %v", v)
+	}
+	rsi.Add(11)
+	if v := rsi.Value(); v != 100 {
+		t.Fatalf("RSI without losses want 100 got %v", v)
+	}
+}
@@ -0,0 +1,115 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/szmcdull/quantainer"
+)
+
+type (
+	// Bollinger is the Bollinger bands: the SMA of the last period values,
+	// plus and minus k population standard deviations.
+	Bollinger struct {
+		rs quantainer.RollingStats[float64]
+		k  float64
+	}
+
+	// ATR is Wilder's average true range over period bars.
+	ATR struct {
+		period    int
+		n         int
+		prevClose float64
+		value     float64
+	}
+)
+
+// NewBollinger creates Bollinger bands, usually with period=20 and k=2.
+func NewBollinger(period int, k float64) *Bollinger {
+	return &Bollinger{
+		rs: *quantainer.NewRollingStats[float64](period),
+		k:  k,
+	}
+}
+
+func (me *Bollinger) Add(v float64) {
+	me.rs.AddLast(v)
+}
+
+// Ready returns true once period values were added.
+func (me *Bollinger) Ready() bool {
+	return me.rs.Filled()
+}
+
+// Value returns the middle band.
+func (me *Bollinger) Value() float64 {
+	return me.Middle()
+}
+
+func (me *Bollinger) Middle() float64 {
+	if !me.Ready() {
+		return math.NaN()
+	}
+	return me.rs.Mean()
+}
+
+func (me *Bollinger) Upper() float64 {
+	return me.Middle() + me.k*me.rs.StdDev()
+}
+
+func (me *Bollinger) Lower() float64 {
+	return me.Middle() - me.k*me.rs.StdDev()
+}
+
+// PercentB returns where the last value lies between the bands: 0 on the lower band and 1 on the upper one.
+func (me *Bollinger) PercentB() float64 {
+	if !me.Ready() {
+		return math.NaN()
+	}
+	lower, upper := me.Lower(), me.Upper()
+	return (*me.rs.Last() - lower) / (upper - lower)
+}
+
+func (me *Bollinger) Clear() {
+	me.rs.Clear()
+}
+
+func NewATR(period int) *ATR {
+	return &ATR{
+		period: period,
+	}
+}
+
+// AddBar adds a bar. The true range of the first bar is its high-low range.
+func (me *ATR) AddBar(bar quantainer.Bar) {
+	tr := bar.High - bar.Low
+	if me.n > 0 {
+		tr = trueRange(bar, me.prevClose)
+	}
+	me.prevClose = bar.Close
+	me.n++
+	p := float64(me.period)
+	if me.n <= me.period {
+		me.value += tr
+		if me.n == me.period {
+			me.value /= p
+		}
+		return
+	}
+	me.value = (me.value*(p-1) + tr) / p
+}
+
+// Ready returns true once period bars were added.
+func (me *ATR) Ready() bool {
+	return me.period > 0 && me.n >= me.period
+}
+
+func (me *ATR) Value() float64 {
+	if !me.Ready() {
+		return math.NaN()
+	}
+	return me.value
+}
+
+func (me *ATR) Clear() {
+	*me = ATR{period: me.period}
+}
@@ -0,0 +1,49 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVolatility(t *testing.T) {
+	nan := math.NaN()
+	bollinger, atr := NewBollinger(20, 2), NewATR(14)
+	runIndicatorCases(t, []indicatorCase{
+		{"Bollinger", func(i int) { bollinger.Add(closes[i]) }, bollinger.Ready, []indicatorOutput{
+			{"Middle", bollinger.Value, map[int]float64{
+				15: nan, 19: 101.9285, 25: 103.379, 27: 103.6455, 30: 103.97, 33: 104.4335, 39: 106.0345,
+			}},
+			{"Upper", bollinger.Upper, map[int]float64{
+				15: nan, 19: 107.3977568965, 25: 111.5931899175, 27: 112.2536158798, 30: 112.5612630038,
+				33: 112.0711705218, 39: 111.7443983353,
+			}},
+			{"Lower", bollinger.Lower, map[int]float64{
+				15: nan, 19: 96.4592431035, 25: 95.1648100825, 27: 95.0373841202, 30: 95.3787369962,
+				33: 96.7958294782, 39: 100.3246016647,
+			}},
+			{"PercentB", bollinger.PercentB, map[int]float64{
+				15: nan, 19: 0.666521708, 25: 0.91154393, 27: 0.7041387482, 39: 0.8122209705,
+			}},
+		}},
+		// TA-Lib has no true range for the first bar, so its values were computed with a leading copy
+		// of the first bar, whose close lies within the first bar's range and makes its true range high-low.
+		{"ATR", func(i int) { atr.AddBar(bar(i)) }, atr.Ready, []indicatorOutput{
+			{"Value", atr.Value, map[int]float64{
+				9: nan, 13: 1.9392857143, 14: 1.943622449, 15: 1.9226494169, 19: 1.9653266867, 25: 1.9603381456,
+				27: 2.0373323806, 30: 1.9834326677, 33: 1.9649167533, 39: 2.0588096436,
+			}},
+		}},
+	})
+}
+
+func TestBollinger_PercentBEmpty(t *testing.T) {
+	bollinger := NewBollinger(20, 2)
+	if !math.IsNaN(bollinger.PercentB()) {
+		t.Fatal("Expected NaN %B from an empty indicator")
+	}
+	bollinger.Add(1)
+	bollinger.Clear()
+	if !math.IsNaN(bollinger.PercentB()) {
+		t.Fatal("Expected NaN %B from a cleared indicator")
+	}
+}
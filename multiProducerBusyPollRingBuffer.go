@@ -0,0 +1,91 @@
+package quantainer
+
+import "runtime"
+
+// MultiProducerBusyPollRingBuffer is a BusyPollRingBuffer that allows several goroutines to write concurrently.
+// Writers claim sequence numbers with an atomic add and may commit them out of order;
+// readers see the items strictly in sequence order, each item as soon as it and all items before it are committed.
+// Like BusyPollRingBuffer, writers never wait for readers, and readers can see at most Len()-1 items.
+// A writer waits only if it claims a slot whose previous item is still being written by another writer,
+// so every claimed slot must be committed.
+type MultiProducerBusyPollRingBuffer[T any] struct {
+	busyPollRing[T]
+	pending []T // the items between acquire and commit, by slot
+}
+
+func NewMultiProducerBusyPollRingBuffer[T any](size int) *MultiProducerBusyPollRingBuffer[T] {
+	result := &MultiProducerBusyPollRingBuffer[T]{
+		pending: make([]T, size),
+	}
+	result.init(size)
+	return result
+}
+
+// acquire waits until the previous item in the slot of seq is committed, and returns a private copy
+// of the slot for the item to be written into, which commit copies into the slot.
+func (me *MultiProducerBusyPollRingBuffer[T]) acquire(seq uint64) *T {
+	i := me.slot(seq)
+	var prev uint64
+	if size := uint64(len(me.data)); seq >= size {
+		prev = 2*(seq-size) + 2
+	}
+	for me.versions[i].Load() != prev {
+		runtime.Gosched()
+	}
+	return &me.pending[i]
+}
+
+func (me *MultiProducerBusyPollRingBuffer[T]) commit(seq uint64) {
+	i := me.slot(seq)
+	me.writeSlot(i, seq, &me.pending[i])
+	if me.waiters.Load() != 0 {
+		me.wake()
+	}
+}
+
+// advanceFrom moves the first item of the last write, where new readers start, forward to seq.
+// Writes may finish out of order, so a write that finishes after a later one must not move it back.
+func (me *MultiProducerBusyPollRingBuffer[T]) advanceFrom(seq uint64) {
+	for {
+		from := me.from.Load()
+		if from >= seq || me.from.CompareAndSwap(from, seq) {
+			return
+		}
+	}
+}
+
+func (me *MultiProducerBusyPollRingBuffer[T]) Write(v T) {
+	seq := me.seq.Add(1) - 1
+	*me.acquire(seq) = v
+	me.commit(seq)
+	me.advanceFrom(seq)
+}
+
+// BeginWrite claims the next slot and returns a pointer to it so the item can be written in place,
+// along with its sequence number to be passed to EndWrite.
+// Readers do not see the item, or any item after it, until EndWrite is called.
+func (me *MultiProducerBusyPollRingBuffer[T]) BeginWrite() (ptr *T, seq uint64) {
+	seq = me.seq.Add(1) - 1
+	return me.acquire(seq), seq
+}
+
+// EndWrite commits the item claimed by BeginWrite.
+func (me *MultiProducerBusyPollRingBuffer[T]) EndWrite(seq uint64) {
+	me.commit(seq)
+	me.advanceFrom(seq)
+}
+
+// WriteMany claims consecutive sequence numbers for all of vs at once.
+func (me *MultiProducerBusyPollRingBuffer[T]) WriteMany(vs []T) {
+	n := uint64(len(vs))
+	if n == 0 {
+		return
+	}
+	from := me.seq.Add(n) - n
+	for i, v := range vs {
+		seq := from + uint64(i)
+		*me.acquire(seq) = v
+		me.commit(seq)
+	}
+	me.advanceFrom(from)
+}
@@ -0,0 +1,164 @@
+package quantainer
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMultiProducerBusyPollRingBuffer_Write(t *testing.T) {
+	buf := NewMultiProducerBusyPollRingBuffer[int](4)
+	reader := buf.Reader()
+
+	buf.Write(1)
+	buf.WriteMany([]int{2, 3})
+
+	var val int
+	for i, exp := range []int{1, 2, 3} {
+		seq, lost, ok := reader.ReadSeq(&val)
+		if !ok || lost != 0 || seq != uint64(i) || val != exp {
+			t.Fatalf("Read %d: got seq=%d lost=%d ok=%v val=%d", i, seq, lost, ok, val)
+		}
+	}
+	if reader.Read(&val) {
+		t.Fatal("Expected no more data")
+	}
+	if buf.Seq() != 3 || buf.Len() != 4 {
+		t.Fatalf("Seq/Len want 3/4 got %d/%d", buf.Seq(), buf.Len())
+	}
+}
+
+func TestMultiProducerBusyPollRingBuffer_OutOfOrderCommit(t *testing.T) {
+	buf := NewMultiProducerBusyPollRingBuffer[int](4)
+	reader := buf.Reader()
+
+	p1, s1 := buf.BeginWrite()
+	p2, s2 := buf.BeginWrite()
+	if s1 != 0 || s2 != 1 {
+		t.Fatalf("claimed seq want 0,1 got %d,%d", s1, s2)
+	}
+	*p2 = 20
+	buf.EndWrite(s2)
+
+	// item 1 is committed but item 0 is not, so nothing is visible yet
+	var val int
+	if _, lost, ok := reader.ReadSeq(&val); ok || lost != 0 {
+		t.Fatalf("Expected no data before item 0 is committed, got lost=%d ok=%v", lost, ok)
+	}
+
+	*p1 = 10
+	buf.EndWrite(s1)
+	for _, exp := range []int{10, 20} {
+		if ok := reader.Read(&val); !ok || val != exp {
+			t.Fatalf("Expected %d, got %d (ok=%v)", exp, val, ok)
+		}
+	}
+
+	// item 0 finished last, but new readers still start from the last write, item 1
+	if seq := buf.Reader().Next(); seq != s2 {
+		t.Fatalf("Expected a new reader to start at %d, got %d", s2, seq)
+	}
+}
+
+func TestMultiProducerBusyPollRingBuffer_Overrun(t *testing.T) {
+	buf := NewMultiProducerBusyPollRingBuffer[int](4)
+	reader := buf.Reader()
+	for i := 0; i < 6; i++ {
+		buf.Write(i)
+	}
+
+	var val int
+	if _, lost, ok := reader.ReadSeq(&val); ok || lost != 3 {
+		t.Fatalf("Expected overrun with 3 items lost, got lost=%d ok=%v", lost, ok)
+	}
+	for _, exp := range []int{3, 4, 5} {
+		if seq, _, ok := reader.ReadSeq(&val); !ok || seq != uint64(exp) || val != exp {
+			t.Fatalf("Expected seq %d, got seq=%d ok=%v val=%d", exp, seq, ok, val)
+		}
+	}
+}
+
+// Concurrent producers and readers: items are never torn, every sequence number is either read or reported lost,
+// and items of each producer are seen in the order that producer wrote them.
+func TestMultiProducerBusyPollRingBuffer_Concurrent(t *testing.T) {
+	type item struct {
+		Producer, N, Check int
+	}
+	const producers, perProducer = 4, 10000
+	buf := NewMultiProducerBusyPollRingBuffer[item](128)
+	readers := make([]*BusyPollRingBufferReader[item], 2)
+	for i := range readers {
+		readers[i] = buf.Reader()
+	}
+
+	var wg sync.WaitGroup
+	var done atomic.Bool
+	for _, reader := range readers {
+		wg.Add(1)
+		go func(reader *BusyPollRingBufferReader[item]) {
+			defer wg.Done()
+			var val item
+			var expected, read, lost uint64
+			last := make([]int, producers)
+			for i := range last {
+				last[i] = -1
+			}
+			for {
+				finished := done.Load()
+				seq, l, ok := reader.ReadSeq(&val)
+				switch {
+				case ok:
+					if seq != expected || val.Check != val.Producer*perProducer+val.N || val.N <= last[val.Producer] {
+						t.Errorf("seq %d (expected %d): torn or out of order item %+v", seq, expected, val)
+						return
+					}
+					last[val.Producer] = val.N
+					expected++
+					read++
+				case l != 0:
+					expected += l
+					lost += l
+				case finished:
+					if read+lost != producers*perProducer {
+						t.Errorf("read %d + lost %d != written %d", read, lost, producers*perProducer)
+					}
+					return
+				default:
+					runtime.Gosched()
+				}
+			}
+		}(reader)
+	}
+
+	var pwg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		pwg.Add(1)
+		go func(p int) {
+			defer pwg.Done()
+			for n := 0; n < perProducer; n++ {
+				if n%2 == 0 {
+					buf.Write(item{Producer: p, N: n, Check: p*perProducer + n})
+				} else {
+					ptr, seq := buf.BeginWrite()
+					*ptr = item{Producer: p, N: n, Check: p*perProducer + n}
+					buf.EndWrite(seq)
+				}
+			}
+		}(p)
+	}
+	pwg.Wait()
+	done.Store(true)
+	wg.Wait()
+}
+
+func BenchmarkMultiProducerBusyPollRingBuffer_Write(b *testing.B) {
+	buf := NewMultiProducerBusyPollRingBuffer[int](1000)
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			buf.Write(i)
+			i++
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package quantainer
+
+import "golang.org/x/exp/constraints"
+
+type (
+	osNode[T constraints.Ordered] struct {
+		key         T
+		count       int // multiplicity of key
+		size        int // total multiplicity of the subtree
+		height      int
+		left, right *osNode[T]
+	}
+
+	// orderStatTree is an AVL tree of distinct keys with multiplicities.
+	// Every node keeps the total count of its subtree, so rank and select queries are O(log n).
+	orderStatTree[T constraints.Ordered] struct {
+		root *osNode[T]
+	}
+)
+
+func (me *osNode[T]) getSize() int {
+	if me == nil {
+		return 0
+	}
+	return me.size
+}
+
+func (me *osNode[T]) getHeight() int {
+	if me == nil {
+		return 0
+	}
+	return me.height
+}
+
+func (me *osNode[T]) update() {
+	me.size = me.left.getSize() + me.count + me.right.getSize()
+	hl, hr := me.left.getHeight(), me.right.getHeight()
+	if hl > hr {
+		me.height = hl + 1
+	} else {
+		me.height = hr + 1
+	}
+}
+
+func (me *osNode[T]) rotateRight() *osNode[T] {
+	l := me.left
+	me.left = l.right
+	l.right = me
+	me.update()
+	l.update()
+	return l
+}
+
+func (me *osNode[T]) rotateLeft() *osNode[T] {
+	r := me.right
+	me.right = r.left
+	r.left = me
+	me.update()
+	r.update()
+	return r
+}
+
+func (me *osNode[T]) balance() *osNode[T] {
+	me.update()
+	switch bf := me.left.getHeight() - me.right.getHeight(); {
+	case bf > 1:
+		if me.left.left.getHeight() < me.left.right.getHeight() {
+			me.left = me.left.rotateLeft()
+		}
+		return me.rotateRight()
+	case bf < -1:
+		if me.right.right.getHeight() < me.right.left.getHeight() {
+			me.right = me.right.rotateRight()
+		}
+		return me.rotateLeft()
+	}
+	return me
+}
+
+func osInsert[T constraints.Ordered](n *osNode[T], v T) *osNode[T] {
+	if n == nil {
+		return &osNode[T]{key: v, count: 1, size: 1, height: 1}
+	}
+	switch {
+	case v < n.key:
+		n.left = osInsert(n.left, v)
+	case n.key < v:
+		n.right = osInsert(n.right, v)
+	default:
+		n.count++
+		n.size++
+		return n
+	}
+	return n.balance()
+}
+
+// osRemoveMin detaches the leftmost node of n and returns it with the new subtree root.
+func osRemoveMin[T constraints.Ordered](n *osNode[T]) (root, min *osNode[T]) {
+	if n.left == nil {
+		return n.right, n
+	}
+	n.left, min = osRemoveMin(n.left)
+	return n.balance(), min
+}
+
+func osRemove[T constraints.Ordered](n *osNode[T], v T) (root *osNode[T], ok bool) {
+	if n == nil {
+		return nil, false
+	}
+	switch {
+	case v < n.key:
+		n.left, ok = osRemove(n.left, v)
+	case n.key < v:
+		n.right, ok = osRemove(n.right, v)
+	default:
+		ok = true
+		if n.count > 1 {
+			n.count--
+			n.size--
+			return n, true
+		}
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+		var min *osNode[T]
+		n.right, min = osRemoveMin(n.right)
+		min.left, min.right = n.left, n.right
+		return min.balance(), true
+	}
+	if !ok {
+		return n, false
+	}
+	return n.balance(), true
+}
+
+func (me *orderStatTree[T]) Add(v T) {
+	me.root = osInsert(me.root, v)
+}
+
+// Remove removes one occurrence of v. It returns false if v is not in the tree.
+func (me *orderStatTree[T]) Remove(v T) bool {
+	root, ok := osRemove(me.root, v)
+	me.root = root
+	return ok
+}
+
+// Len returns the total number of elements, counting duplicates.
+func (me *orderStatTree[T]) Len() int {
+	return me.root.getSize()
+}
+
+func (me *orderStatTree[T]) Clear() {
+	me.root = nil
+}
+
+// Rank returns the number of elements strictly less than v.
+func (me *orderStatTree[T]) Rank(v T) (result int) {
+	for n := me.root; n != nil; {
+		switch {
+		case v < n.key:
+			n = n.left
+		case n.key < v:
+			result += n.left.getSize() + n.count
+			n = n.right
+		default:
+			return result + n.left.getSize()
+		}
+	}
+	return
+}
+
+// Select returns the k-th smallest element (0-based, counting duplicates).
+func (me *orderStatTree[T]) Select(k int) (result T, ok bool) {
+	if k < 0 || k >= me.root.getSize() {
+		return
+	}
+	for n := me.root; n != nil; {
+		ls := n.left.getSize()
+		switch {
+		case k < ls:
+			n = n.left
+		case k < ls+n.count:
+			return n.key, true
+		default:
+			k -= ls + n.count
+			n = n.right
+		}
+	}
+	return
+}
+
+// Ascend calls f for every distinct key in ascending order with its multiplicity.
+func (me *orderStatTree[T]) Ascend(f func(key T, count int)) {
+	osAscend(me.root, f)
+}
+
+func osAscend[T constraints.Ordered](n *osNode[T], f func(key T, count int)) {
+	for n != nil {
+		osAscend(n.left, f)
+		f(n.key, n.count)
+		n = n.right
+	}
+}
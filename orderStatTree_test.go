@@ -0,0 +1,119 @@
+package quantainer
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// checkOsNode validates AVL balance and subtree counts, returning the subtree height and size.
+func checkOsNode[T Number](t *testing.T, n *osNode[T]) (height, size int) {
+	t.Helper()
+	if n == nil {
+		return 0, 0
+	}
+	if n.count <= 0 {
+		t.Fatalf("node %v has count %d", n.key, n.count)
+	}
+	hl, sl := checkOsNode(t, n.left)
+	hr, sr := checkOsNode(t, n.right)
+	if hl-hr > 1 || hr-hl > 1 {
+		t.Fatalf("node %v unbalanced: %d vs %d", n.key, hl, hr)
+	}
+	height = hl + 1
+	if hr >= hl {
+		height = hr + 1
+	}
+	size = sl + n.count + sr
+	if n.height != height || n.size != size {
+		t.Fatalf("node %v stale: height %d/%d size %d/%d", n.key, n.height, height, n.size, size)
+	}
+	return
+}
+
+func TestOrderStatTree_MatchesSortedSlice(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var tree orderStatTree[int]
+	var ref []int
+	for i := 0; i < 5000; i++ {
+		if len(ref) > 0 && r.Intn(3) == 0 {
+			idx := r.Intn(len(ref))
+			if !tree.Remove(ref[idx]) {
+				t.Fatalf("Remove(%d) returned false", ref[idx])
+			}
+			ref = append(ref[:idx], ref[idx+1:]...)
+		} else {
+			v := r.Intn(100)
+			tree.Add(v)
+			ref = append(ref, v)
+			sort.Ints(ref)
+		}
+		checkOsNode(t, tree.root)
+		if tree.Len() != len(ref) {
+			t.Fatalf("Len got %d want %d", tree.Len(), len(ref))
+		}
+		k := r.Intn(len(ref) + 1)
+		got, ok := tree.Select(k)
+		if ok != (k < len(ref)) || ok && got != ref[k] {
+			t.Fatalf("Select(%d) got %v,%v in %v", k, got, ok, ref)
+		}
+		v := r.Intn(102) - 1
+		if got, want := tree.Rank(v), sort.SearchInts(ref, v); got != want {
+			t.Fatalf("Rank(%d) got %d want %d", v, got, want)
+		}
+	}
+}
+
+func TestOrderStatTree_RemoveMissing(t *testing.T) {
+	var tree orderStatTree[int]
+	tree.Add(1)
+	if tree.Remove(2) {
+		t.Fatalf("Remove of missing key should return false")
+	}
+	if !tree.Remove(1) || tree.Len() != 0 {
+		t.Fatalf("Remove(1) should empty the tree")
+	}
+	if _, ok := tree.Select(0); ok {
+		t.Fatalf("Select on empty tree should fail")
+	}
+}
+
+func TestPercentile_Interpolation(t *testing.T) {
+	var tree orderStatTree[int]
+	for _, v := range []int{4, 1, 3, 2} {
+		tree.Add(v)
+	}
+	// reference values from numpy.percentile([1, 2, 3, 4], p, method=...)
+	tests := []struct {
+		p             float64
+		interpolation Interpolation
+		want          float64
+	}{
+		{40, InterpolationLinear, 2.2},
+		{40, InterpolationLower, 2},
+		{40, InterpolationHigher, 3},
+		{40, InterpolationNearest, 2},
+		{40, InterpolationMidpoint, 2.5},
+		{50, InterpolationLinear, 2.5},
+		{50, InterpolationNearest, 3},
+		{90, InterpolationNearest, 4},
+		{0, InterpolationLinear, 1},
+		{100, InterpolationLinear, 4},
+	}
+	for _, tt := range tests {
+		got := percentile(tree.Len(), tree.Select, tt.p, tt.interpolation)
+		if d := got - tt.want; d > 1e-12 || d < -1e-12 {
+			t.Errorf("percentile(%v, %v) got %v want %v", tt.p, tt.interpolation, got, tt.want)
+		}
+	}
+}
+
+func TestPercentile_OutOfRangePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic for p > 100")
+		}
+	}()
+	var tree orderStatTree[int]
+	percentile(0, tree.Select, 101, InterpolationLinear)
+}
@@ -0,0 +1,194 @@
+package quantainer
+
+import "math"
+
+// OutlierMethod selects how OutlierFilter measures the spread of the window.
+type OutlierMethod int
+
+const (
+	// OutlierMAD scores values by their distance from the median in median absolute deviations,
+	// scaled by 1.4826 to estimate the standard deviation of normally distributed values.
+	OutlierMAD OutlierMethod = iota
+	// OutlierIQR scores values by their distance outside the interquartile range, in IQRs (Tukey's fences).
+	OutlierIQR
+)
+
+// madScale makes the MAD a consistent estimator of the standard deviation of a normal distribution.
+const madScale = 1.4826
+
+// OutlierFilter classifies values as outliers by their robust score against a rolling window,
+// using the order statistics of a SortedRingBuffer.
+// Values are only classified once the window is Filled; before that they are all inliers.
+type OutlierFilter[T Number] struct {
+	window    SortedRingBuffer[T]
+	method    OutlierMethod
+	threshold float64
+	reject    bool
+}
+
+// NewOutlierFilter creates a filter over the last size admitted values that flags values
+// whose score is beyond threshold, e.g. 3 for OutlierMAD or 1.5 for OutlierIQR.
+func NewOutlierFilter[T Number](size int, method OutlierMethod, threshold float64) *OutlierFilter[T] {
+	return &OutlierFilter[T]{
+		window:    *NewSortedRingBuffer[T](size),
+		method:    method,
+		threshold: threshold,
+	}
+}
+
+// NewOutlierFilterConfigurable creates an outlier filter with a configurable window size.
+// If maxSize is changed at runtime, the window will not resize until a value is admitted.
+func NewOutlierFilterConfigurable[T Number](maxSize func() int, method OutlierMethod, threshold float64) *OutlierFilter[T] {
+	return &OutlierFilter[T]{
+		window:    *NewSortedRingBufferConfigurable[T](maxSize),
+		method:    method,
+		threshold: threshold,
+	}
+}
+
+// SetRejectOutliers sets whether outliers are kept out of the window, so bad ticks cannot shift it.
+// A lasting level shift is then rejected until the window is cleared, so it suits filtering bad ticks
+// better than tracking a moving series.
+func (me *OutlierFilter[T]) SetRejectOutliers(reject bool) {
+	me.reject = reject
+}
+
+// Add classifies v against the window, then admits it unless it is an outlier and outliers are rejected.
+func (me *OutlierFilter[T]) Add(v T) (outlier bool) {
+	outlier = me.IsOutlier(v)
+	if !outlier || !me.reject {
+		me.window.AddLast(v)
+	}
+	return
+}
+
+// IsOutlier returns whether v would be an outlier, without adding it.
+func (me *OutlierFilter[T]) IsOutlier(v T) bool {
+	if !me.window.Filled() {
+		return false
+	}
+	return math.Abs(me.Score(v)) > me.threshold
+}
+
+// Score returns the signed robust score of v: its distance from the median in scaled MADs for OutlierMAD,
+// or its distance outside the interquartile range in IQRs for OutlierIQR.
+// It is ±Inf if the window has no spread and v differs from it, and NaN if the window is empty.
+func (me *OutlierFilter[T]) Score(v T) float64 {
+	x := float64(v)
+	if me.method == OutlierIQR {
+		q1, q3 := me.Quartiles()
+		var d float64
+		if x < q1 {
+			d = x - q1
+		} else if x > q3 {
+			d = x - q3
+		}
+		return robustScore(d, q3-q1)
+	}
+	return robustScore(x-me.Median(), madScale*me.MAD())
+}
+
+func robustScore(d, scale float64) float64 {
+	if d == 0 {
+		return 0
+	}
+	return d / scale
+}
+
+// Median returns the median of the window, or NaN if it is empty.
+func (me *OutlierFilter[T]) Median() float64 {
+	return SortedRingBufferMedian(&me.window)
+}
+
+// Quartiles returns the first and third quartiles of the window, or NaN if it is empty.
+func (me *OutlierFilter[T]) Quartiles() (q1, q3 float64) {
+	return SortedRingBufferPercentile(&me.window, 25, InterpolationLinear),
+		SortedRingBufferPercentile(&me.window, 75, InterpolationLinear)
+}
+
+// MAD returns the median absolute deviation from the median of the window, unscaled, or NaN if it is empty.
+// It takes O(log² n): the deviations of the elements below and above the median are two sorted sequences
+// read through Select, and their median is found by binary search.
+func (me *OutlierFilter[T]) MAD() float64 {
+	n := me.window.Count()
+	if n == 0 {
+		return math.NaN()
+	}
+	m := me.Median()
+	at := func(k int) float64 {
+		v, _ := me.window.Select(k)
+		return float64(v)
+	}
+	// s is the number of elements below the median
+	lo, hi := 0, n
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if at(mid) < m {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	s := lo
+	below := func(i int) float64 { return m - at(s-1-i) } // ascending in i
+	above := func(j int) float64 { return at(s+j) - m }   // ascending in j
+
+	// kth returns the k-th smallest deviation, taking i from below and k+1-i from above
+	kth := func(k int) float64 {
+		lo, hi := k+1-(n-s), k+1
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > s {
+			hi = s
+		}
+		for lo < hi {
+			i := (lo + hi) / 2
+			if j := k + 1 - i; j > 0 && below(i) < above(j-1) {
+				lo = i + 1
+			} else {
+				hi = i
+			}
+		}
+		i, j := lo, k+1-lo
+		result := math.Inf(-1)
+		if i > 0 {
+			result = below(i - 1)
+		}
+		if j > 0 {
+			result = math.Max(result, above(j-1))
+		}
+		return result
+	}
+	if n%2 == 1 {
+		return kth(n / 2)
+	}
+	return (kth(n/2-1) + kth(n/2)) / 2
+}
+
+// Window returns the sorted window of admitted values.
+func (me *OutlierFilter[T]) Window() *SortedRingBuffer[T] {
+	return &me.window
+}
+
+func (me *OutlierFilter[T]) Count() int {
+	return me.window.Count()
+}
+
+func (me *OutlierFilter[T]) Clear() {
+	me.window.Clear()
+}
+
+func (me *OutlierFilter[T]) MaxSize() int {
+	return me.window.MaxSize()
+}
+
+// Full returns true if the window's max size is reached.
+func (me *OutlierFilter[T]) Full() bool {
+	return me.window.Full()
+}
+
+// Filled is the same as Full except it returns false if the window's size is 0
+func (me *OutlierFilter[T]) Filled() bool {
+	return me.window.Filled()
+}
@@ -0,0 +1,131 @@
+package quantainer
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func ExampleOutlierFilter_Add() {
+	f := NewOutlierFilter[float64](5, OutlierMAD, 3)
+	f.SetRejectOutliers(true)
+	for _, price := range []float64{100, 101, 100.5, 99.5, 100, 1000, 100.2} {
+		fmt.Println(price, f.Add(price))
+	}
+	fmt.Println(f.Median())
+	// Output:
+	// 100 false
+	// 101 false
+	// 100.5 false
+	// 99.5 false
+	// 100 false
+	// 1000 true
+	// 100.2 false
+	// 100.2
+}
+
+// naiveMAD sorts the deviations from the median.
+func naiveMAD(values []float64) float64 {
+	median := func(s []float64) float64 {
+		s = append([]float64(nil), s...)
+		sort.Float64s(s)
+		n := len(s)
+		if n%2 == 1 {
+			return s[n/2]
+		}
+		return (s[n/2-1] + s[n/2]) / 2
+	}
+	m := median(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - m)
+	}
+	return median(deviations)
+}
+
+func TestOutlierFilter_MAD(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, size := range []int{1, 2, 3, 4, 7, 50} {
+		f := NewOutlierFilter[int](size, OutlierMAD, 3)
+		if !math.IsNaN(f.MAD()) {
+			t.Fatal("Expected NaN MAD for an empty window")
+		}
+		for i := 0; i < 500; i++ {
+			f.Add(r.Intn(20)) // many duplicates
+			values := make([]float64, 0, size)
+			for _, v := range f.Window().ToSlice() {
+				values = append(values, float64(v))
+			}
+			if got, want := f.MAD(), naiveMAD(values); got != want {
+				t.Fatalf("size %d step %d: MAD of %v got %v want %v", size, i, values, got, want)
+			}
+		}
+	}
+
+	g := NewOutlierFilter[float64](31, OutlierMAD, 3)
+	for i := 0; i < 1000; i++ {
+		g.Add(r.NormFloat64())
+		if got, want := g.MAD(), naiveMAD(g.Window().ToSlice()); got != want {
+			t.Fatalf("step %d: MAD got %v want %v", i, got, want)
+		}
+	}
+}
+
+func TestOutlierFilter_Classify(t *testing.T) {
+	f := NewOutlierFilter[float64](5, OutlierMAD, 3)
+	for _, v := range []float64{10, 12, 11, 9, 13} {
+		if f.Add(v) {
+			t.Fatalf("Expected %v to be admitted while warming up", v)
+		}
+	}
+	// median 11, MAD 1
+	if s := f.Score(15); math.Abs(s-4/madScale) > 1e-12 {
+		t.Fatalf("score of 15 want %v got %v", 4/madScale, s)
+	}
+	if f.IsOutlier(15) || !f.IsOutlier(16) || !f.IsOutlier(6) {
+		t.Fatal("Expected values beyond 3 scaled MADs to be outliers")
+	}
+	// without rejection, outliers are still admitted
+	if !f.Add(100) || *f.Window().Last() != 100 {
+		t.Fatal("Expected 100 to be flagged and admitted")
+	}
+
+	iqr := NewOutlierFilter[int](8, OutlierIQR, 1.5)
+	iqr.SetRejectOutliers(true)
+	for _, v := range []int{1, 2, 3, 4, 5, 6, 7, 8} {
+		iqr.Add(v)
+	}
+	// Q1 2.75, Q3 6.25, fences at -2.5 and 11.5
+	if q1, q3 := iqr.Quartiles(); q1 != 2.75 || q3 != 6.25 {
+		t.Fatalf("Quartiles want 2.75 6.25 got %v %v", q1, q3)
+	}
+	if s := iqr.Score(5); s != 0 {
+		t.Fatalf("Expected score 0 inside the quartiles, got %v", s)
+	}
+	if iqr.IsOutlier(11) || !iqr.IsOutlier(12) || !iqr.Add(-3) || !iqr.Add(100) {
+		t.Fatal("Unexpected IQR classification")
+	}
+	if iqr.Count() != 8 || *iqr.Window().Last() != 8 {
+		t.Fatalf("Expected the outliers to be rejected, got %v", iqr.Window().ToSlice())
+	}
+}
+
+func TestOutlierFilter_NoSpread(t *testing.T) {
+	f := NewOutlierFilterConfigurable[int](func() int { return 3 }, OutlierMAD, 3)
+	f.SetRejectOutliers(true)
+	for i := 0; i < 3; i++ {
+		f.Add(5)
+	}
+	if !f.Filled() || f.MaxSize() != 3 || !f.Full() {
+		t.Fatal("Expected a filled window")
+	}
+	if f.Add(5) || !f.Add(6) || f.Score(6) != math.Inf(1) || f.Score(4) != math.Inf(-1) {
+		t.Fatal("Expected any different value to be an outlier in a window without spread")
+	}
+	f.Clear()
+	if f.Count() != 0 || f.Filled() || f.Add(6) {
+		t.Fatal("Expected values to be admitted after Clear")
+	}
+}
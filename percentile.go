@@ -0,0 +1,48 @@
+package quantainer
+
+import "math"
+
+// Interpolation selects the value Percentile returns when the requested percentile
+// falls between two elements i < j. The methods match numpy.percentile.
+type Interpolation int
+
+const (
+	InterpolationLinear   Interpolation = iota // i + (j - i) * fraction
+	InterpolationLower                         // i
+	InterpolationHigher                        // j
+	InterpolationNearest                       // i or j, whichever is nearest (ties go to the even index)
+	InterpolationMidpoint                      // (i + j) / 2
+)
+
+// percentile computes the p-th percentile (0 <= p <= 100) of n sorted elements,
+// fetching the k-th smallest element with sel. It returns NaN if n is 0.
+func percentile[T Number](n int, sel func(k int) (T, bool), p float64, interpolation Interpolation) float64 {
+	if !(p >= 0 && p <= 100) {
+		panic("percentile must be in the range [0, 100]")
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	pos := p / 100 * float64(n-1)
+	lo := math.Floor(pos)
+	frac := pos - lo
+	i, _ := sel(int(lo))
+	if frac == 0 {
+		return float64(i)
+	}
+	j, _ := sel(int(lo) + 1)
+	switch interpolation {
+	case InterpolationLower:
+		return float64(i)
+	case InterpolationHigher:
+		return float64(j)
+	case InterpolationNearest:
+		if math.RoundToEven(pos) == lo {
+			return float64(i)
+		}
+		return float64(j)
+	case InterpolationMidpoint:
+		return (float64(i) + float64(j)) / 2
+	}
+	return float64(i) + (float64(j)-float64(i))*frac
+}
@@ -16,6 +16,7 @@ type (
 		tail    int // Points to the next available position
 		count   int // Number of elements in the buffer
 		maxSize func() int
+		onEvict func(T) // called with every element dropped by AddLast
 	}
 )
 
@@ -62,6 +63,21 @@ func (me *RingBuffer[T]) head() int {
 // AddLast adds an element to the end of the ring buffer.
 // If the buffer is full, it will overwrite the oldest element.
 func (me *RingBuffer[T]) AddLast(v T) {
+	if !me.addLast(v, me.onEvict) && me.onEvict != nil {
+		me.onEvict(v)
+	}
+}
+
+// OnEvict sets fn to be called with every element AddLast drops: the oldest element when the buffer is full,
+// the oldest elements that no longer fit when maxSize shrank, and the added element itself if maxSize is 0.
+func (me *RingBuffer[T]) OnEvict(fn func(v T)) {
+	me.onEvict = fn
+}
+
+// addLast is AddLast that calls onEvict (if not nil) with every element dropped from the buffer,
+// either overwritten or discarded because maxSize shrank.
+// It returns false if v is not stored because maxSize is 0.
+func (me *RingBuffer[T]) addLast(v T, onEvict func(T)) bool {
 	size := me.maxSize()
 	oldSize := len(me.data)
 	head := me.head()
@@ -81,6 +97,11 @@ func (me *RingBuffer[T]) AddLast(v T) {
 		me.data = data
 	} else if sizeDiff < 0 { // maxSize reduced
 		count := me.count
+		if onEvict != nil {
+			for i := 0; i < count-size; i++ {
+				onEvict(*me.At(i))
+			}
+		}
 		me.count = size
 		me.data = me.ToSlice()
 		if count > size {
@@ -92,19 +113,22 @@ func (me *RingBuffer[T]) AddLast(v T) {
 
 	if size == 0 {
 		me.tail = 0
-		return
+		return false
 	}
 
-	me.data[tail] = v
-	tail++
 	if me.count < size {
 		me.count++
+	} else if onEvict != nil {
+		onEvict(me.data[tail]) // the oldest element when full
 	}
+	me.data[tail] = v
+	tail++
 
 	if tail >= size {
 		tail -= size
 	}
 	me.tail = tail
+	return true
 }
 
 func (me *RingBuffer[T]) PopFirst() (result *T) {
@@ -117,6 +141,20 @@ func (me *RingBuffer[T]) PopFirst() (result *T) {
 	return
 }
 
+func (me *RingBuffer[T]) PopLast() (result *T) {
+	if me.count == 0 {
+		return nil
+	}
+	tail := me.tail - 1
+	if tail < 0 {
+		tail += len(me.data)
+	}
+	result = &me.data[tail]
+	me.tail = tail
+	me.count--
+	return
+}
+
 func (me *RingBuffer[T]) First() (result *T) {
 	if me.count == 0 {
 		return nil
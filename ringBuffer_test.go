@@ -409,3 +409,55 @@ func TestRingBuffer_Shrink_KeepLatest(t *testing.T) {
 		}
 	}
 }
+
+func TestRingBuffer_PopLast(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	if rb.PopLast() != nil {
+		t.Fatalf("PopLast on empty want nil")
+	}
+	rb.AddLast(1)
+	rb.AddLast(2)
+	rb.AddLast(3)
+	rb.AddLast(4) // [2 3 4], tail wrapped to 1
+	if v := rb.PopLast(); v == nil || *v != 4 {
+		t.Fatalf("PopLast want 4 got %v", v)
+	}
+	if v := rb.PopLast(); v == nil || *v != 3 {
+		t.Fatalf("PopLast want 3 got %v", v)
+	}
+	rb.AddLast(5)
+	if got := rb.ToSlice(); !equalSlice(got, []int{2, 5}) {
+		t.Fatalf("ToSlice after PopLast want [2 5] got %v", got)
+	}
+}
+
+func TestRingBuffer_OnEvict(t *testing.T) {
+	size := 3
+	rb := NewRingBufferConfigurable[int](func() int { return size })
+	var evicted []int
+	rb.OnEvict(func(v int) { evicted = append(evicted, v) })
+
+	for i := 1; i <= 5; i++ {
+		rb.AddLast(i)
+	}
+	if !equalSlice(evicted, []int{1, 2}) {
+		t.Fatalf("evicted want [1 2] got %v", evicted)
+	}
+
+	// shrinking drops the oldest elements that no longer fit
+	size = 1
+	rb.AddLast(6)
+	if !equalSlice(evicted, []int{1, 2, 3, 4, 5}) || !equalSlice(rb.ToSlice(), []int{6}) {
+		t.Fatalf("after shrinking evicted %v, kept %v", evicted, rb.ToSlice())
+	}
+
+	// popped and cleared elements are not evicted
+	rb.PopFirst()
+	rb.AddLast(7)
+	rb.Clear()
+	size = 0
+	rb.AddLast(8)
+	if !equalSlice(evicted, []int{1, 2, 3, 4, 5, 8}) {
+		t.Fatalf("evicted want [1 2 3 4 5 8] got %v", evicted)
+	}
+}
@@ -0,0 +1,198 @@
+package quantainer
+
+import "math"
+
+// RollingCovariance is a pair of RingBuffers holding two aligned series, x and y, that maintains their means,
+// variances and covariance in O(1) per update with Welford's update, like RollingStats.
+// Beta and Alpha regress y on x, so x is the benchmark (e.g. market returns) and y the asset.
+type RollingCovariance[T Number] struct {
+	xs, ys       RingBuffer[T]
+	meanX, meanY float64
+	m2X, m2Y     float64 // sums of squared deviations from the means
+	cXY          float64 // sum of the products of the deviations from the means
+	maxSize      func() int
+	size         int // maxSize as last read, shared by xs and ys so they never differ
+}
+
+func NewRollingCovariance[T Number](size int) *RollingCovariance[T] {
+	return &RollingCovariance[T]{
+		xs: NewRingBuffer[T](size),
+		ys: NewRingBuffer[T](size),
+	}
+}
+
+// NewRollingCovarianceConfigurable creates a new rolling window with a configurable maximum size.
+// If maxSize shrinks at runtime, the oldest pairs are removed from the statistics on the next AddLast.
+func NewRollingCovarianceConfigurable[T Number](maxSize func() int) *RollingCovariance[T] {
+	result := &RollingCovariance[T]{maxSize: maxSize, size: maxSize()}
+	size := func() int {
+		return result.size
+	}
+	result.xs = *NewRingBufferConfigurable[T](size)
+	result.ys = *NewRingBufferConfigurable[T](size)
+	return result
+}
+
+func (me *RollingCovariance[T]) add(x, y T) {
+	fx, fy := float64(x), float64(y)
+	n := float64(me.xs.count)
+	dx, dy := fx-me.meanX, fy-me.meanY
+	me.meanX += dx / n
+	me.meanY += dy / n
+	me.m2X += dx * (fx - me.meanX)
+	me.m2Y += dy * (fy - me.meanY)
+	me.cXY += dx * (fy - me.meanY)
+}
+
+func (me *RollingCovariance[T]) remove(x, y T) {
+	if me.xs.count == 0 {
+		me.reset()
+		return
+	}
+	fx, fy := float64(x), float64(y)
+	n := float64(me.xs.count)
+	dx, dy := fx-me.meanX, fy-me.meanY
+	me.meanX -= dx / n
+	me.meanY -= dy / n
+	me.m2X -= dx * (fx - me.meanX)
+	me.m2Y -= dy * (fy - me.meanY)
+	me.cXY -= dx * (fy - me.meanY)
+	if me.m2X < 0 {
+		me.m2X = 0
+	}
+	if me.m2Y < 0 {
+		me.m2Y = 0
+	}
+}
+
+func (me *RollingCovariance[T]) reset() {
+	me.meanX, me.meanY = 0, 0
+	me.m2X, me.m2Y = 0, 0
+	me.cXY = 0
+}
+
+// AddLast adds a pair to the end of the window.
+// If the window is full, the oldest pair is evicted first.
+func (me *RollingCovariance[T]) AddLast(x, y T) {
+	if me.maxSize != nil {
+		me.size = me.maxSize() // read once, so a concurrent change cannot size xs and ys differently
+	}
+	size := me.xs.maxSize()
+	for me.xs.count > 0 && me.xs.count >= size {
+		me.PopFirst()
+	}
+	if size == 0 {
+		return
+	}
+	me.xs.AddLast(x)
+	me.ys.AddLast(y)
+	me.add(x, y)
+}
+
+// PopFirst removes the oldest pair. x and y are nil if the window is empty.
+func (me *RollingCovariance[T]) PopFirst() (x, y *T) {
+	x, y = me.xs.PopFirst(), me.ys.PopFirst()
+	if x != nil {
+		me.remove(*x, *y)
+	}
+	return
+}
+
+// MeanX returns the mean of x, or NaN if the window is empty.
+func (me *RollingCovariance[T]) MeanX() float64 {
+	if me.xs.count == 0 {
+		return math.NaN()
+	}
+	return me.meanX
+}
+
+// MeanY returns the mean of y, or NaN if the window is empty.
+func (me *RollingCovariance[T]) MeanY() float64 {
+	if me.xs.count == 0 {
+		return math.NaN()
+	}
+	return me.meanY
+}
+
+// VarianceX returns the population variance of x, or NaN if the window is empty.
+func (me *RollingCovariance[T]) VarianceX() float64 {
+	if me.xs.count == 0 {
+		return math.NaN()
+	}
+	return me.m2X / float64(me.xs.count)
+}
+
+// VarianceY returns the population variance of y, or NaN if the window is empty.
+func (me *RollingCovariance[T]) VarianceY() float64 {
+	if me.xs.count == 0 {
+		return math.NaN()
+	}
+	return me.m2Y / float64(me.xs.count)
+}
+
+// Covariance returns the population covariance, or NaN if the window is empty.
+func (me *RollingCovariance[T]) Covariance() float64 {
+	if me.xs.count == 0 {
+		return math.NaN()
+	}
+	return me.cXY / float64(me.xs.count)
+}
+
+// SampleCovariance returns the unbiased sample covariance, or NaN if the window has less than 2 pairs.
+func (me *RollingCovariance[T]) SampleCovariance() float64 {
+	if me.xs.count < 2 {
+		return math.NaN()
+	}
+	return me.cXY / float64(me.xs.count-1)
+}
+
+// Correlation returns the Pearson correlation in [-1, 1], or NaN if either series is constant in the window.
+func (me *RollingCovariance[T]) Correlation() float64 {
+	if me.m2X <= 0 || me.m2Y <= 0 {
+		return math.NaN()
+	}
+	r := me.cXY / math.Sqrt(me.m2X*me.m2Y)
+	return math.Max(-1, math.Min(1, r))
+}
+
+// Beta returns the slope of the least-squares regression of y on x, or NaN if x is constant in the window.
+func (me *RollingCovariance[T]) Beta() float64 {
+	if me.m2X <= 0 {
+		return math.NaN()
+	}
+	return me.cXY / me.m2X
+}
+
+// Alpha returns the intercept of the least-squares regression of y on x, or NaN if x is constant in the window.
+func (me *RollingCovariance[T]) Alpha() float64 {
+	return me.meanY - me.Beta()*me.meanX
+}
+
+// ToSlices returns the x and y series, from the oldest to the newest.
+func (me *RollingCovariance[T]) ToSlices() (xs, ys []T) {
+	return me.xs.ToSlice(), me.ys.ToSlice()
+}
+
+func (me *RollingCovariance[T]) Count() int {
+	return me.xs.count
+}
+
+func (me *RollingCovariance[T]) Clear() {
+	me.xs.Clear()
+	me.ys.Clear()
+	me.reset()
+}
+
+func (me *RollingCovariance[T]) MaxSize() int {
+	return me.xs.maxSize()
+}
+
+// Full returns true if the window's max size is reached.
+func (me *RollingCovariance[T]) Full() bool {
+	return me.xs.Full()
+}
+
+// Filled is the same as Full except it returns false if the window's size is 0
+func (me *RollingCovariance[T]) Filled() bool {
+	return me.xs.Filled()
+}
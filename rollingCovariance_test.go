@@ -0,0 +1,143 @@
+package quantainer
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func ExampleRollingCovariance_Beta() {
+	c := NewRollingCovariance[float64](3)
+	c.AddLast(100, 0) // evicted
+	c.AddLast(1, 3)
+	c.AddLast(2, 5)
+	c.AddLast(3, 7)
+	fmt.Printf("%.6f %.6f %.6f\n", c.Beta(), c.Alpha(), c.Correlation())
+	// Output:
+	// 2.000000 1.000000 1.000000
+}
+
+// naive population covariance, correlation and beta for reference
+func naiveCovariance(xs, ys []float64) (cov, corr, beta float64) {
+	mx, vx := naiveMeanVar(xs)
+	my, vy := naiveMeanVar(ys)
+	for i := range xs {
+		cov += (xs[i] - mx) * (ys[i] - my)
+	}
+	cov /= float64(len(xs))
+	return cov, cov / math.Sqrt(vx*vy), cov / vx
+}
+
+func assertRollingCovariance(t *testing.T, c *RollingCovariance[float64], step int) {
+	t.Helper()
+	xs, ys := c.ToSlices()
+	cov, corr, beta := naiveCovariance(xs, ys)
+	if math.Abs(c.Covariance()-cov) > 1e-6 {
+		t.Fatalf("step %d: covariance got %v want %v", step, c.Covariance(), cov)
+	}
+	if math.Abs(c.Correlation()-corr) > 1e-9 {
+		t.Fatalf("step %d: correlation got %v want %v", step, c.Correlation(), corr)
+	}
+	if math.Abs(c.Beta()-beta) > 1e-9 {
+		t.Fatalf("step %d: beta got %v want %v", step, c.Beta(), beta)
+	}
+	mx, _ := naiveMeanVar(xs)
+	my, _ := naiveMeanVar(ys)
+	if alpha := my - beta*mx; math.Abs(c.Alpha()-alpha) > 1e-6 {
+		t.Fatalf("step %d: alpha got %v want %v", step, c.Alpha(), alpha)
+	}
+}
+
+func TestRollingCovariance_MatchesNaive(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	c := NewRollingCovariance[float64](20)
+	for i := 0; i < 10000; i++ {
+		x := r.NormFloat64()
+		c.AddLast(x+100, 0.8*x+r.NormFloat64()*0.5+50)
+		if i > 0 {
+			assertRollingCovariance(t, c, i)
+		}
+	}
+	if corr := c.Correlation(); corr < 0.5 || corr > 1 {
+		t.Fatalf("Expected a strong positive correlation, got %v", corr)
+	}
+}
+
+func TestRollingCovariance_Filled(t *testing.T) {
+	c := NewRollingCovariance[int](3)
+	if !math.IsNaN(c.Covariance()) || !math.IsNaN(c.Beta()) || !math.IsNaN(c.Alpha()) || c.Filled() {
+		t.Fatal("Expected NaN statistics and not Filled for an empty window")
+	}
+	c.AddLast(1, 1)
+	if c.Covariance() != 0 || !math.IsNaN(c.SampleCovariance()) || !math.IsNaN(c.Correlation()) {
+		t.Fatal("Expected undefined correlation with a single pair")
+	}
+	c.AddLast(2, 1)
+	c.AddLast(3, 1)
+	if !c.Filled() || c.Beta() != 0 || c.Alpha() != 1 || !math.IsNaN(c.Correlation()) {
+		t.Fatalf("Expected beta 0 and undefined correlation for a constant y, got %v %v %v", c.Beta(), c.Alpha(), c.Correlation())
+	}
+
+	if x, y := c.PopFirst(); *x != 1 || *y != 1 || c.Count() != 2 || c.Filled() {
+		t.Fatalf("Unexpected PopFirst %v %v", *x, *y)
+	}
+	c.Clear()
+	if c.Count() != 0 || !math.IsNaN(c.MeanX()) {
+		t.Fatal("Expected an empty window after Clear")
+	}
+	if x, y := c.PopFirst(); x != nil || y != nil {
+		t.Fatal("Expected nil from PopFirst on an empty window")
+	}
+}
+
+func TestRollingCovariance_Configurable(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	size := 10
+	c := NewRollingCovarianceConfigurable[float64](func() int { return size })
+	for i := 0; i < 300; i++ {
+		switch i {
+		case 100:
+			size = 4
+		case 200:
+			size = 15
+		}
+		c.AddLast(r.Float64(), r.Float64())
+		if c.Count() > size {
+			t.Fatalf("step %d: count %d exceeds max size %d", i, c.Count(), size)
+		}
+		if i > 0 {
+			assertRollingCovariance(t, c, i)
+		}
+	}
+	if !c.Filled() || c.MaxSize() != 15 {
+		t.Fatalf("Expected a filled window of 15, got %d/%d", c.Count(), c.MaxSize())
+	}
+
+	size = 0
+	c.AddLast(1, 2)
+	if c.Count() != 0 || c.Filled() || !math.IsNaN(c.Covariance()) {
+		t.Fatal("Expected an empty window of size 0")
+	}
+}
+
+// maxSize is read once per AddLast, so xs and ys stay aligned even if it changes between calls.
+func TestRollingCovariance_ConfigurableReadOnce(t *testing.T) {
+	calls := 0
+	c := NewRollingCovarianceConfigurable[int](func() int {
+		calls++
+		return 3 + calls%2*2 // alternates between 5 and 3
+	})
+	for i := 0; i < 20; i++ {
+		c.AddLast(i, 2*i)
+		xs, ys := c.ToSlices()
+		if len(xs) != len(ys) || len(xs) != c.Count() {
+			t.Fatalf("step %d: misaligned series %v %v", i, xs, ys)
+		}
+		for j := range xs {
+			if ys[j] != 2*xs[j] {
+				t.Fatalf("step %d: misaligned series %v %v", i, xs, ys)
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package quantainer
+
+import "golang.org/x/exp/constraints"
+
+type (
+	minMaxEntry[T any] struct {
+		seq int
+		v   T
+	}
+
+	// RollingMinMax is a RingBuffer that tracks the minimum and maximum of its elements
+	// using monotonic deques, at O(1) amortized cost per update.
+	// When several elements share the extreme value, ArgMin/ArgMax report the oldest one.
+	RollingMinMax[T constraints.Ordered] struct {
+		rb       RingBuffer[T]
+		seq      int // sequence number of the next element to add
+		min, max RingBuffer[minMaxEntry[T]]
+		maxSize  func() int
+		size     int // maxSize as last read, shared by rb, min and max so they are trimmed alike
+	}
+)
+
+func NewRollingMinMax[T constraints.Ordered](size int) *RollingMinMax[T] {
+	return &RollingMinMax[T]{
+		rb:  NewRingBuffer[T](size),
+		min: NewRingBuffer[minMaxEntry[T]](size),
+		max: NewRingBuffer[minMaxEntry[T]](size),
+	}
+}
+
+// NewRollingMinMaxConfigurable creates a new rolling min/max window with a configurable maximum size.
+// If maxSize shrinks at runtime, the oldest elements are evicted on the next AddLast.
+func NewRollingMinMaxConfigurable[T constraints.Ordered](maxSize func() int) *RollingMinMax[T] {
+	result := &RollingMinMax[T]{maxSize: maxSize, size: maxSize()}
+	size := func() int {
+		return result.size
+	}
+	result.rb = *NewRingBufferConfigurable[T](size)
+	result.min = *NewRingBufferConfigurable[minMaxEntry[T]](size)
+	result.max = *NewRingBufferConfigurable[minMaxEntry[T]](size)
+	return result
+}
+
+// headSeq returns the sequence number of the oldest element.
+func (me *RollingMinMax[T]) headSeq() int {
+	return me.seq - me.rb.count
+}
+
+// AddLast adds an element to the end of the window.
+// If the window is full, the oldest element is evicted first.
+func (me *RollingMinMax[T]) AddLast(v T) {
+	if me.maxSize != nil {
+		me.size = me.maxSize() // read once, so the deques cannot keep elements that left the window
+	}
+	size := me.rb.maxSize()
+	for me.rb.count > 0 && me.rb.count >= size {
+		me.PopFirst()
+	}
+	if size == 0 {
+		return
+	}
+	me.rb.AddLast(v)
+	for e := me.min.Last(); e != nil && v < e.v; e = me.min.Last() {
+		me.min.PopLast()
+	}
+	for e := me.max.Last(); e != nil && v > e.v; e = me.max.Last() {
+		me.max.PopLast()
+	}
+	me.min.AddLast(minMaxEntry[T]{seq: me.seq, v: v})
+	me.max.AddLast(minMaxEntry[T]{seq: me.seq, v: v})
+	me.seq++
+}
+
+func (me *RollingMinMax[T]) PopFirst() (result *T) {
+	head := me.headSeq()
+	result = me.rb.PopFirst()
+	if result == nil {
+		return
+	}
+	if e := me.min.First(); e != nil && e.seq == head {
+		me.min.PopFirst()
+	}
+	if e := me.max.First(); e != nil && e.seq == head {
+		me.max.PopFirst()
+	}
+	return
+}
+
+// Min returns the smallest element in the window, or nil if the window is empty.
+func (me *RollingMinMax[T]) Min() *T {
+	e := me.min.First()
+	if e == nil {
+		return nil
+	}
+	return &e.v
+}
+
+// Max returns the largest element in the window, or nil if the window is empty.
+func (me *RollingMinMax[T]) Max() *T {
+	e := me.max.First()
+	if e == nil {
+		return nil
+	}
+	return &e.v
+}
+
+// ArgMin returns the index (as understood by At) of the smallest element, or -1 if the window is empty.
+func (me *RollingMinMax[T]) ArgMin() int {
+	e := me.min.First()
+	if e == nil {
+		return -1
+	}
+	return e.seq - me.headSeq()
+}
+
+// ArgMax returns the index (as understood by At) of the largest element, or -1 if the window is empty.
+func (me *RollingMinMax[T]) ArgMax() int {
+	e := me.max.First()
+	if e == nil {
+		return -1
+	}
+	return e.seq - me.headSeq()
+}
+
+func (me *RollingMinMax[T]) ToSlice() []T {
+	return me.rb.ToSlice()
+}
+
+func (me *RollingMinMax[T]) Count() int {
+	return me.rb.count
+}
+
+func (me *RollingMinMax[T]) First() *T {
+	return me.rb.First()
+}
+
+func (me *RollingMinMax[T]) Last() *T {
+	return me.rb.Last()
+}
+
+func (me *RollingMinMax[T]) At(i int) *T {
+	return me.rb.At(i)
+}
+
+func (me *RollingMinMax[T]) Clear() {
+	me.rb.Clear()
+	me.min.Clear()
+	me.max.Clear()
+}
+
+func (me *RollingMinMax[T]) MaxSize() int {
+	return me.rb.maxSize()
+}
+
+// Full returns true if the window's max size is reached.
+func (me *RollingMinMax[T]) Full() bool {
+	return me.rb.Full()
+}
+
+// Filled is the same as Full except it returns false if the window's size is 0
+func (me *RollingMinMax[T]) Filled() bool {
+	return me.rb.Filled()
+}
@@ -0,0 +1,125 @@
+package quantainer
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func ExampleRollingMinMax_Max() {
+	w := NewRollingMinMax[int](3)
+	for _, v := range []int{5, 1, 4, 2, 3} {
+		w.AddLast(v)
+	}
+	// window is [4 2 3]
+	fmt.Println(*w.Min(), w.ArgMin(), *w.Max(), w.ArgMax())
+	// Output:
+	// 2 1 4 0
+}
+
+func assertRollingMinMax(t *testing.T, w *RollingMinMax[int], step int) {
+	t.Helper()
+	values := w.ToSlice()
+	if len(values) == 0 {
+		if w.Min() != nil || w.Max() != nil || w.ArgMin() != -1 || w.ArgMax() != -1 {
+			t.Fatalf("step %d: empty window should have no min/max", step)
+		}
+		return
+	}
+	argMin, argMax := 0, 0
+	for i, v := range values {
+		if v < values[argMin] {
+			argMin = i
+		}
+		if v > values[argMax] {
+			argMax = i
+		}
+	}
+	if *w.Min() != values[argMin] || w.ArgMin() != argMin {
+		t.Fatalf("step %d: min got %v@%d want %v@%d in %v", step, *w.Min(), w.ArgMin(), values[argMin], argMin, values)
+	}
+	if *w.Max() != values[argMax] || w.ArgMax() != argMax {
+		t.Fatalf("step %d: max got %v@%d want %v@%d in %v", step, *w.Max(), w.ArgMax(), values[argMax], argMax, values)
+	}
+	if *w.At(w.ArgMin()) != *w.Min() || *w.At(w.ArgMax()) != *w.Max() {
+		t.Fatalf("step %d: At(ArgMin/ArgMax) does not match Min/Max", step)
+	}
+}
+
+func TestRollingMinMax_MatchesNaive(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	w := NewRollingMinMax[int](7)
+	for i := 0; i < 5000; i++ {
+		w.AddLast(r.Intn(20))
+		assertRollingMinMax(t, w, i)
+	}
+}
+
+func TestRollingMinMax_Monotonic(t *testing.T) {
+	w := NewRollingMinMax[int](3)
+	for i := 0; i < 10; i++ {
+		w.AddLast(i)
+	}
+	if *w.Min() != 7 || *w.Max() != 9 || w.ArgMin() != 0 || w.ArgMax() != 2 {
+		t.Fatalf("increasing: got min %v@%d max %v@%d", *w.Min(), w.ArgMin(), *w.Max(), w.ArgMax())
+	}
+	for i := 10; i > 0; i-- {
+		w.AddLast(i)
+	}
+	if *w.Min() != 1 || *w.Max() != 3 || w.ArgMin() != 2 || w.ArgMax() != 0 {
+		t.Fatalf("decreasing: got min %v@%d max %v@%d", *w.Min(), w.ArgMin(), *w.Max(), w.ArgMax())
+	}
+}
+
+func TestRollingMinMax_Resize(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	size := 5
+	w := NewRollingMinMaxConfigurable[int](func() int { return size })
+	for i := 0; i < 3000; i++ {
+		if i%23 == 0 {
+			size = r.Intn(10)
+		}
+		w.AddLast(r.Intn(50))
+		if w.Count() > size {
+			t.Fatalf("step %d: count %d exceeds size %d", i, w.Count(), size)
+		}
+		assertRollingMinMax(t, w, i)
+	}
+}
+
+func TestRollingMinMax_PopFirstAndClear(t *testing.T) {
+	w := NewRollingMinMax[int](4)
+	w.AddLast(1)
+	w.AddLast(3)
+	w.AddLast(2)
+	if v := w.PopFirst(); v == nil || *v != 1 {
+		t.Fatalf("PopFirst want 1 got %v", v)
+	}
+	assertRollingMinMax(t, w, 0)
+	if w.Filled() {
+		t.Fatalf("Filled want false")
+	}
+
+	w.Clear()
+	if w.Count() != 0 || w.Min() != nil || w.Max() != nil {
+		t.Fatalf("Clear should empty the window")
+	}
+	w.AddLast(5)
+	assertRollingMinMax(t, w, 1)
+}
+
+// maxSize is read once per AddLast, so the deques are trimmed exactly like the window even if it changes between calls.
+func TestRollingMinMax_ConfigurableReadOnce(t *testing.T) {
+	sizes := []int{5, 3, 4, 2, 6}
+	calls := 0
+	w := NewRollingMinMaxConfigurable[int](func() int {
+		result := sizes[calls%len(sizes)]
+		calls++
+		return result
+	})
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 200; i++ {
+		w.AddLast(r.Intn(20))
+		assertRollingMinMax(t, w, i)
+	}
+}
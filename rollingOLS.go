@@ -0,0 +1,352 @@
+package quantainer
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	// ErrSingularWindow is returned by RollingOLS.Fit when the regressors in the window are collinear,
+	// or so close to it that the coefficients would be meaningless.
+	ErrSingularWindow = errors.New("quantainer: singular regression window")
+	// ErrTooFewSamples is returned by RollingOLS.Fit when the window has no more samples than regressors.
+	ErrTooFewSamples = errors.New("quantainer: too few samples for regression")
+)
+
+// olsConditionLimit is the smallest ratio of a Cholesky pivot to the corresponding diagonal element
+// accepted by Fit, i.e. the fraction of the variance of a regressor that the other regressors must not explain.
+const olsConditionLimit = 1e-10
+
+type (
+	olsSample struct {
+		x []float64
+		y float64
+	}
+
+	// RollingOLS fits the linear model y = x·b by ordinary least squares over a sliding window of samples.
+	// It maintains the means of x and y and their centered cross-products with Welford's update in O(k²)
+	// per sample, and solves them on demand with Fit. There is no implicit intercept: include a constant 1
+	// in x to fit one. The other regressors are then solved for around their means, so a large offset,
+	// such as a timestamp, does not cost precision.
+	RollingOLS struct {
+		k     int
+		rb    RingBuffer[olsSample]
+		mean  []float64 // means of the regressors
+		cxx   []float64 // lower triangle of the centered X'X, row by row
+		cxy   []float64 // centered X'y
+		meanY float64
+		cyy   float64
+		runs  []int     // number of the latest samples in which each regressor has kept its last value
+		dev   []float64 // deviations of a sample from the means, reused by update
+		// maxSize is the configured size, read once per AddLast into size, which rb uses,
+		// so a change between two reads cannot drop a sample that is not removed from the sums.
+		maxSize func() int
+		size    int
+	}
+
+	// OLSResult is the fit of a RollingOLS window.
+	OLSResult struct {
+		Coefficients   []float64
+		StdErrors      []float64 // standard errors of the coefficients
+		TStats         []float64 // coefficients divided by their standard errors; infinite or NaN for a perfect fit
+		R2             float64   // centered R², so x should include an intercept; 0 if y is constant
+		ResidualStdDev float64   // square root of the residual sum of squares over n-k degrees of freedom
+		N              int       // number of samples
+	}
+)
+
+// NewRollingOLS creates a regression window of size samples with k regressors.
+func NewRollingOLS(k, size int) *RollingOLS {
+	return newRollingOLS(k, NewRingBuffer[olsSample](size))
+}
+
+// NewRollingOLSConfigurable creates a regression window with a configurable maximum size.
+// If maxSize shrinks at runtime, the oldest samples are removed from the sums on the next AddLast.
+func NewRollingOLSConfigurable(k int, maxSize func() int) *RollingOLS {
+	result := newRollingOLS(k, RingBuffer[olsSample]{})
+	result.maxSize = maxSize
+	result.size = maxSize()
+	result.rb = *NewRingBufferConfigurable[olsSample](func() int {
+		return result.size
+	})
+	return result
+}
+
+func newRollingOLS(k int, rb RingBuffer[olsSample]) *RollingOLS {
+	return &RollingOLS{
+		k:    k,
+		rb:   rb,
+		mean: make([]float64, k),
+		cxx:  make([]float64, k*(k+1)/2),
+		cxy:  make([]float64, k),
+		runs: make([]int, k),
+		dev:  make([]float64, k),
+	}
+}
+
+// update adds (sign 1) or removes (sign -1) a sample that has just been added to or removed from rb.
+func (me *RollingOLS) update(x []float64, y, sign float64) {
+	n := float64(me.rb.count)
+	d := me.dev
+	for i, xi := range x {
+		d[i] = xi - me.mean[i]
+		me.mean[i] += sign * d[i] / n
+	}
+	dy := y - me.meanY
+	me.meanY += sign * dy / n
+	ey := y - me.meanY
+	p := 0
+	for i, di := range d {
+		for j := range d[:i+1] {
+			me.cxx[p] += sign * di * (x[j] - me.mean[j])
+			p++
+		}
+		me.cxy[i] += sign * di * ey
+	}
+	me.cyy += sign * dy * ey
+	if me.cyy < 0 {
+		me.cyy = 0
+	}
+}
+
+func (me *RollingOLS) reset() {
+	for i := range me.mean {
+		me.mean[i] = 0
+		me.cxy[i] = 0
+	}
+	for i := range me.cxx {
+		me.cxx[i] = 0
+	}
+	me.meanY = 0
+	me.cyy = 0
+}
+
+// AddLast adds a sample to the end of the window. x is copied and must have k elements.
+// If the window is full, the oldest sample is evicted first.
+func (me *RollingOLS) AddLast(x []float64, y float64) {
+	if len(x) != me.k {
+		panic("quantainer: RollingOLS.AddLast: wrong number of regressors")
+	}
+	if me.maxSize != nil {
+		me.size = me.maxSize()
+	}
+	size := me.rb.maxSize()
+	if size == 0 {
+		me.Clear()
+		return
+	}
+	last := me.rb.Last()
+	for i, xi := range x {
+		if last != nil && last.x[i] == xi {
+			me.runs[i]++
+		} else {
+			me.runs[i] = 1
+		}
+	}
+	var row []float64
+	for me.rb.count > 0 && me.rb.count >= size {
+		row = me.popFirst()
+	}
+	if row == nil {
+		row = make([]float64, me.k)
+	}
+	copy(row, x)
+	me.rb.AddLast(olsSample{x: row, y: y})
+	me.update(row, y, 1)
+}
+
+// popFirst removes the oldest sample and returns its x for reuse.
+func (me *RollingOLS) popFirst() []float64 {
+	s := me.rb.PopFirst()
+	if me.rb.count == 0 {
+		me.reset()
+	} else {
+		me.update(s.x, s.y, -1)
+	}
+	return s.x
+}
+
+// constant returns the index of the regressor that is constant in the window, or -1 if there is none.
+// It returns ErrSingularWindow if several regressors are constant, or one is constantly 0.
+func (me *RollingOLS) constant() (c int, err error) {
+	c = -1
+	for i, run := range me.runs {
+		if run < me.rb.count {
+			continue
+		}
+		if c >= 0 || me.rb.Last().x[i] == 0 {
+			return -1, ErrSingularWindow
+		}
+		c = i
+	}
+	return
+}
+
+// Fit solves the normal equations of the window.
+// It returns ErrTooFewSamples if there are not more samples than regressors, and ErrSingularWindow
+// if X'X is not safely invertible.
+func (me *RollingOLS) Fit() (result OLSResult, err error) {
+	k, n := me.k, me.rb.count
+	if n <= k {
+		return result, ErrTooFewSamples
+	}
+	c, err := me.constant()
+	if err != nil {
+		return
+	}
+
+	// the regressors to solve for: all of them without an intercept, the others around their means with one
+	cols := make([]int, 0, k)
+	for i := 0; i < k; i++ {
+		if i != c {
+			cols = append(cols, i)
+		}
+	}
+	m := len(cols)
+	a := make([]float64, m*m)
+	xty := make([]float64, m)
+	for r, i := range cols {
+		for s, j := range cols[:r+1] {
+			v := me.cxx[i*(i+1)/2+j]
+			if c < 0 {
+				v += float64(n) * me.mean[i] * me.mean[j]
+			}
+			a[r*m+s] = v
+		}
+		xty[r] = me.cxy[i]
+		if c < 0 {
+			xty[r] += float64(n) * me.mean[i] * me.meanY
+		}
+	}
+	l, err := cholesky(a, m)
+	if err != nil {
+		return result, err
+	}
+	sub := choleskySolve(l, m, xty, make([]float64, m))
+	yty := me.cyy
+	if c < 0 {
+		yty += float64(n) * me.meanY * me.meanY
+	}
+	rss := yty
+	for r := range sub {
+		rss -= sub[r] * xty[r]
+	}
+	rss = math.Max(rss, 0)
+
+	b := make([]float64, k)
+	for r, i := range cols {
+		b[i] = sub[r]
+	}
+	result.Coefficients = b
+	result.N = n
+	if me.cyy > 0 {
+		result.R2 = math.Max(0, 1-rss/me.cyy)
+	}
+	variance := rss / float64(n-k)
+	result.ResidualStdDev = math.Sqrt(variance)
+	result.StdErrors = make([]float64, k)
+	result.TStats = make([]float64, k)
+	e, z := make([]float64, m), make([]float64, m)
+	for r, i := range cols {
+		// the diagonal element of the inverse of the normal equations
+		for s := range e {
+			e[s] = 0
+		}
+		e[r] = 1
+		choleskySolve(l, m, e, z)
+		result.StdErrors[i] = math.Sqrt(variance * z[r])
+	}
+	if c >= 0 {
+		// the intercept makes the line go through the means, with the variance of a mean
+		// plus that of the slopes projected to the means
+		mc := me.rb.Last().x[c] // exact, unlike the mean after the regressor varied earlier
+		v, means := 1/float64(n), make([]float64, m)
+		for r, i := range cols {
+			b[c] -= b[i] * me.mean[i]
+			means[r] = me.mean[i]
+		}
+		b[c] = (b[c] + me.meanY) / mc
+		choleskySolve(l, m, means, z)
+		for r := range means {
+			v += means[r] * z[r]
+		}
+		result.StdErrors[c] = math.Sqrt(variance*v) / math.Abs(mc)
+	}
+	for i := range b {
+		result.TStats[i] = b[i] / result.StdErrors[i]
+	}
+	return
+}
+
+// cholesky returns the lower-triangular L, row by row in a k*k slice, with A = LL'.
+// Only the lower triangle of a, also row by row in a k*k slice, is read.
+func cholesky(a []float64, k int) ([]float64, error) {
+	l := make([]float64, k*k)
+	for i := 0; i < k; i++ {
+		for j := 0; j <= i; j++ {
+			v := a[i*k+j]
+			for m := 0; m < j; m++ {
+				v -= l[i*k+m] * l[j*k+m]
+			}
+			if i != j {
+				l[i*k+j] = v / l[j*k+j]
+				continue
+			}
+			if !(v > olsConditionLimit*a[i*k+i]) {
+				return nil, ErrSingularWindow
+			}
+			l[i*k+i] = math.Sqrt(v)
+		}
+	}
+	return l, nil
+}
+
+// choleskySolve solves LL'x = b into x, which may alias b.
+func choleskySolve(l []float64, k int, b, x []float64) []float64 {
+	for i := 0; i < k; i++ {
+		v := b[i]
+		for m := 0; m < i; m++ {
+			v -= l[i*k+m] * x[m]
+		}
+		x[i] = v / l[i*k+i]
+	}
+	for i := k - 1; i >= 0; i-- {
+		v := x[i]
+		for m := i + 1; m < k; m++ {
+			v -= l[m*k+i] * x[m]
+		}
+		x[i] = v / l[i*k+i]
+	}
+	return x
+}
+
+// K returns the number of regressors.
+func (me *RollingOLS) K() int {
+	return me.k
+}
+
+func (me *RollingOLS) Count() int {
+	return me.rb.count
+}
+
+func (me *RollingOLS) Clear() {
+	me.rb.Clear()
+	me.reset()
+	for i := range me.runs {
+		me.runs[i] = 0
+	}
+}
+
+func (me *RollingOLS) MaxSize() int {
+	return me.rb.maxSize()
+}
+
+// Full returns true if the window's max size is reached.
+func (me *RollingOLS) Full() bool {
+	return me.rb.Full()
+}
+
+// Filled is the same as Full except it returns false if the window's size is 0
+func (me *RollingOLS) Filled() bool {
+	return me.rb.Filled()
+}
@@ -0,0 +1,262 @@
+package quantainer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func ExampleRollingOLS_Fit() {
+	ols := NewRollingOLS(2, 4)
+	for x := 0.0; x < 6; x++ {
+		ols.AddLast([]float64{1, x}, 1+2*x) // intercept and slope
+	}
+	fit, err := ols.Fit()
+	fmt.Printf("%.6f %.6f %v %v %v\n", fit.Coefficients[0], fit.Coefficients[1], fit.R2, fit.ResidualStdDev, err)
+	// Output:
+	// 1.000000 2.000000 1 0 <nil>
+}
+
+// naiveOLS solves the normal equations of the samples by Gaussian elimination,
+// returning the coefficients and the residual sum of squares.
+func naiveOLS(xs [][]float64, ys []float64) (b []float64, rss float64) {
+	k := len(xs[0])
+	a := make([][]float64, k)
+	for i := range a {
+		a[i] = make([]float64, k+1)
+		for s := range xs {
+			for j := 0; j < k; j++ {
+				a[i][j] += xs[s][i] * xs[s][j]
+			}
+			a[i][k] += xs[s][i] * ys[s]
+		}
+	}
+	for c := 0; c < k; c++ {
+		for r := c + 1; r < k; r++ {
+			f := a[r][c] / a[c][c]
+			for j := c; j <= k; j++ {
+				a[r][j] -= f * a[c][j]
+			}
+		}
+	}
+	b = make([]float64, k)
+	for i := k - 1; i >= 0; i-- {
+		v := a[i][k]
+		for j := i + 1; j < k; j++ {
+			v -= a[i][j] * b[j]
+		}
+		b[i] = v / a[i][i]
+	}
+	for s := range xs {
+		e := ys[s]
+		for j := range b {
+			e -= xs[s][j] * b[j]
+		}
+		rss += e * e
+	}
+	return
+}
+
+func TestRollingOLS_MatchesNaive(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const size = 30
+	ols := NewRollingOLS(3, size)
+	var xs [][]float64
+	var ys []float64
+	for i := 0; i < 2000; i++ {
+		x := []float64{1, r.NormFloat64(), r.NormFloat64()*3 + 10}
+		y := 0.5 + 2*x[1] - 0.3*x[2] + r.NormFloat64()
+		ols.AddLast(x, y)
+		xs, ys = append(xs, x), append(ys, y)
+		if len(xs) > size {
+			xs, ys = xs[1:], ys[1:]
+		}
+		if len(xs) <= 3 {
+			if _, err := ols.Fit(); err != ErrTooFewSamples {
+				t.Fatalf("step %d: want ErrTooFewSamples got %v", i, err)
+			}
+			continue
+		}
+		fit, err := ols.Fit()
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+		b, rss := naiveOLS(xs, ys)
+		for j := range b {
+			if math.Abs(fit.Coefficients[j]-b[j]) > 1e-6 {
+				t.Fatalf("step %d: coefficients got %v want %v", i, fit.Coefficients, b)
+			}
+		}
+		if sd := math.Sqrt(rss / float64(len(xs)-3)); math.Abs(fit.ResidualStdDev-sd) > 1e-6 {
+			t.Fatalf("step %d: residual stddev got %v want %v", i, fit.ResidualStdDev, sd)
+		}
+		if fit.N != len(xs) {
+			t.Fatalf("step %d: N got %d want %d", i, fit.N, len(xs))
+		}
+	}
+
+	fit, _ := ols.Fit()
+	if fit.R2 < 0.5 || fit.R2 > 1 || math.Abs(fit.TStats[1]) < 5 {
+		t.Fatalf("Expected a significant fit, got R2 %v t-stats %v", fit.R2, fit.TStats)
+	}
+}
+
+// With a single regressor and an intercept, the standard errors have closed forms.
+func TestRollingOLS_StdErrors(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 5}
+	ys := []float64{2.1, 3.9, 6.2, 7.8, 10.1}
+	ols := NewRollingOLS(2, 5)
+	for i := range xs {
+		ols.AddLast([]float64{1, xs[i]}, ys[i])
+	}
+	fit, err := ols.Fit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, rss := naiveOLS([][]float64{{1, 1}, {1, 2}, {1, 3}, {1, 4}, {1, 5}}, ys)
+	mean, variance := naiveMeanVar(xs)
+	sxx := variance * 5
+	s2 := rss / 3
+	seSlope := math.Sqrt(s2 / sxx)
+	seIntercept := math.Sqrt(s2 * (1/5.0 + mean*mean/sxx))
+	if math.Abs(fit.StdErrors[1]-seSlope) > 1e-9 || math.Abs(fit.StdErrors[0]-seIntercept) > 1e-9 {
+		t.Fatalf("standard errors got %v want [%v %v]", fit.StdErrors, seIntercept, seSlope)
+	}
+	if math.Abs(fit.TStats[1]-b[1]/seSlope) > 1e-6 {
+		t.Fatalf("slope t-stat got %v want %v", fit.TStats[1], b[1]/seSlope)
+	}
+	_, yVariance := naiveMeanVar(ys)
+	if r2 := 1 - rss/(yVariance*5); math.Abs(fit.R2-r2) > 1e-9 {
+		t.Fatalf("R2 got %v want %v", fit.R2, r2)
+	}
+}
+
+func TestRollingOLS_Singular(t *testing.T) {
+	ols := NewRollingOLS(3, 10)
+	for i := 0; i < 10; i++ {
+		x := float64(i)
+		ols.AddLast([]float64{1, x, 2*x + 1}, x) // the third regressor is a combination of the others
+	}
+	if _, err := ols.Fit(); !errors.Is(err, ErrSingularWindow) {
+		t.Fatalf("want ErrSingularWindow got %v", err)
+	}
+
+	// a regressor that is constant in the window is collinear with the intercept
+	ols = NewRollingOLS(2, 3)
+	for i := 0; i < 5; i++ {
+		ols.AddLast([]float64{1, float64(i % 2)}, float64(i))
+	}
+	if _, err := ols.Fit(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		ols.AddLast([]float64{1, 7}, float64(i))
+	}
+	if _, err := ols.Fit(); !errors.Is(err, ErrSingularWindow) {
+		t.Fatalf("want ErrSingularWindow for a constant regressor got %v", err)
+	}
+}
+
+// A regressor with a large mean, such as a timestamp, is only collinear with the intercept in uncentered sums.
+func TestRollingOLS_LargeMean(t *testing.T) {
+	const t0 = 1.7e9
+	ols := NewRollingOLS(2, 50)
+	for i := 0; i < 5000; i++ {
+		x := t0 + float64(i)
+		ols.AddLast([]float64{1, x}, 2+0.5*(x-t0)+math.Sin(float64(i)))
+	}
+	fit, err := ols.Fit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var xs [][]float64
+	var ys []float64
+	for i := 5000 - 50; i < 5000; i++ {
+		xs = append(xs, []float64{1, float64(i)})
+		ys = append(ys, 2+0.5*float64(i)+math.Sin(float64(i)))
+	}
+	b, rss := naiveOLS(xs, ys) // the same window without the offset
+	if math.Abs(fit.Coefficients[1]-b[1]) > 1e-9 {
+		t.Fatalf("slope got %v want %v", fit.Coefficients[1], b[1])
+	}
+	if want := b[0] - b[1]*t0; math.Abs(fit.Coefficients[0]-want) > 1e-9*math.Abs(want) {
+		t.Fatalf("intercept got %v want %v", fit.Coefficients[0], want)
+	}
+	if sd := math.Sqrt(rss / 48); math.Abs(fit.ResidualStdDev-sd) > 1e-9 {
+		t.Fatalf("residual stddev got %v want %v", fit.ResidualStdDev, sd)
+	}
+}
+
+func TestRollingOLS_Configurable(t *testing.T) {
+	size := 8
+	ols := NewRollingOLSConfigurable(2, func() int { return size })
+	for i := 0; i < 20; i++ {
+		if i == 10 {
+			size = 4
+		}
+		x := float64(i)
+		ols.AddLast([]float64{1, x}, 3-x+float64(i%2)*0.5)
+	}
+	if ols.Count() != 4 || !ols.Filled() || ols.MaxSize() != 4 || ols.K() != 2 {
+		t.Fatalf("Unexpected window %d/%d", ols.Count(), ols.MaxSize())
+	}
+	fit, err := ols.Fit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := naiveOLS([][]float64{{1, 16}, {1, 17}, {1, 18}, {1, 19}}, []float64{-13, -13.5, -15, -15.5})
+	if math.Abs(fit.Coefficients[0]-b[0]) > 1e-6 || math.Abs(fit.Coefficients[1]-b[1]) > 1e-6 {
+		t.Fatalf("coefficients got %v want %v", fit.Coefficients, b)
+	}
+
+	// constant y
+	ols.Clear()
+	for i := 0; i < 4; i++ {
+		ols.AddLast([]float64{1, float64(i)}, 5)
+	}
+	if fit, err = ols.Fit(); err != nil || fit.R2 != 0 || math.Abs(fit.Coefficients[0]-5) > 1e-9 {
+		t.Fatalf("Unexpected fit of a constant %+v %v", fit, err)
+	}
+
+	size = 0
+	ols.AddLast([]float64{1, 1}, 1)
+	if ols.Count() != 0 || ols.Filled() {
+		t.Fatal("Expected an empty window of size 0")
+	}
+}
+
+func TestRollingOLS_WrongRegressors(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected a panic for the wrong number of regressors")
+		}
+	}()
+	NewRollingOLS(2, 4).AddLast([]float64{1}, 1)
+}
+
+// maxSize is read once per AddLast, so the running means track the samples actually in the window.
+func TestRollingOLS_ConfigurableReadOnce(t *testing.T) {
+	sizes := []int{5, 3, 4, 2, 6}
+	calls := 0
+	ols := NewRollingOLSConfigurable(2, func() int {
+		result := sizes[calls%len(sizes)]
+		calls++
+		return result
+	})
+	for i := 0; i < 50; i++ {
+		x := float64(i)
+		ols.AddLast([]float64{1, x}, 2*x)
+		var sumX, sumY float64
+		for j := 0; j < ols.rb.count; j++ {
+			s := ols.rb.At(j)
+			sumX += s.x[1]
+			sumY += s.y
+		}
+		n := float64(ols.rb.count)
+		if math.Abs(ols.mean[1]-sumX/n) > 1e-9 || math.Abs(ols.meanY-sumY/n) > 1e-9 {
+			t.Fatalf("step %d: running means %v %v, window means %v %v", i, ols.mean[1], ols.meanY, sumX/n, sumY/n)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package quantainer
+
+import "math"
+
+type (
+	// quantileHeap is a binary heap of window slots ordered by their values,
+	// recording the position of every slot so any slot can be removed in O(log n).
+	quantileHeap[T Number] struct {
+		slots []int32
+		q     *RollingQuantile[T]
+		max   bool // max-heap instead of min-heap
+		upper bool // value of RollingQuantile.upper for the slots of this heap
+	}
+
+	// RollingQuantile tracks a percentile of the last size elements in O(log n) per update without allocating:
+	// the window, a max-heap of its lower elements and a min-heap of the others are preallocated at creation.
+	// The lower heap holds the elements up to the one the percentile falls on, so the percentile is read
+	// from the tops of both heaps. Unlike SortedRingBuffer, it cannot answer other order statistics.
+	RollingQuantile[T Number] struct {
+		vals          []T     // the window, by slot
+		pos           []int32 // position of each slot in its heap
+		upper         []bool  // whether each slot is in the upper heap
+		head, count   int     // slot of the oldest element and number of elements
+		lower, higher quantileHeap[T]
+		p             float64
+		interpolation Interpolation
+	}
+)
+
+func (me *quantileHeap[T]) len() int {
+	return len(me.slots)
+}
+
+func (me *quantileHeap[T]) top() T {
+	return me.q.vals[me.slots[0]]
+}
+
+// before returns whether the slot at position i belongs above the one at position j.
+func (me *quantileHeap[T]) before(i, j int) bool {
+	a, b := me.q.vals[me.slots[i]], me.q.vals[me.slots[j]]
+	if me.max {
+		return a > b
+	}
+	return a < b
+}
+
+func (me *quantileHeap[T]) swap(i, j int) {
+	me.slots[i], me.slots[j] = me.slots[j], me.slots[i]
+	me.q.pos[me.slots[i]] = int32(i)
+	me.q.pos[me.slots[j]] = int32(j)
+}
+
+func (me *quantileHeap[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !me.before(i, parent) {
+			return
+		}
+		me.swap(i, parent)
+		i = parent
+	}
+}
+
+func (me *quantileHeap[T]) down(i int) {
+	n := len(me.slots)
+	for {
+		first := i
+		if l := 2*i + 1; l < n && me.before(l, first) {
+			first = l
+		}
+		if r := 2*i + 2; r < n && me.before(r, first) {
+			first = r
+		}
+		if first == i {
+			return
+		}
+		me.swap(i, first)
+		i = first
+	}
+}
+
+func (me *quantileHeap[T]) push(slot int32) {
+	i := len(me.slots)
+	me.slots = append(me.slots, slot) // within the preallocated capacity
+	me.q.pos[slot] = int32(i)
+	me.q.upper[slot] = me.upper
+	me.up(i)
+}
+
+// remove removes the slot at position i and returns it.
+func (me *quantileHeap[T]) remove(i int) int32 {
+	slot := me.slots[i]
+	last := len(me.slots) - 1
+	if i != last {
+		me.swap(i, last)
+	}
+	me.slots = me.slots[:last]
+	if i != last {
+		me.down(i)
+		me.up(i)
+	}
+	return slot
+}
+
+// NewRollingQuantile creates a window of size elements tracking their p-th percentile (0 <= p <= 100),
+// interpolated like SortedRingBufferPercentile.
+func NewRollingQuantile[T Number](size int, p float64, interpolation Interpolation) *RollingQuantile[T] {
+	if !(p >= 0 && p <= 100) {
+		panic("percentile must be in the range [0, 100]")
+	}
+	result := &RollingQuantile[T]{
+		vals:          make([]T, size),
+		pos:           make([]int32, size),
+		upper:         make([]bool, size),
+		p:             p,
+		interpolation: interpolation,
+	}
+	slots := make([]int32, 2*size)
+	result.lower = quantileHeap[T]{slots: slots[:0:size], q: result, max: true}
+	result.higher = quantileHeap[T]{slots: slots[size:size], q: result, upper: true}
+	return result
+}
+
+// NewRollingMedian creates a window of size elements tracking their median.
+func NewRollingMedian[T Number](size int) *RollingQuantile[T] {
+	return NewRollingQuantile[T](size, 50, InterpolationLinear)
+}
+
+// AddLast adds an element to the end of the window.
+// If the window is full, the oldest element is evicted first.
+func (me *RollingQuantile[T]) AddLast(v T) {
+	size := len(me.vals)
+	if size == 0 {
+		return
+	}
+	if me.count == size {
+		me.PopFirst()
+	}
+	slot := int32((me.head + me.count) % size)
+	me.count++
+	me.vals[slot] = v
+	if me.lower.len() == 0 || v <= me.lower.top() {
+		me.lower.push(slot)
+	} else {
+		me.higher.push(slot)
+	}
+	me.rebalance()
+}
+
+// PopFirst removes the oldest element. ok is false if the window is empty.
+func (me *RollingQuantile[T]) PopFirst() (result T, ok bool) {
+	if me.count == 0 {
+		return
+	}
+	slot := me.head
+	result = me.vals[slot]
+	if me.upper[slot] {
+		me.higher.remove(int(me.pos[slot]))
+	} else {
+		me.lower.remove(int(me.pos[slot]))
+	}
+	me.head = (me.head + 1) % len(me.vals)
+	me.count--
+	me.rebalance()
+	return result, true
+}
+
+// rebalance moves heap tops until the lower heap holds the elements up to the one the percentile falls on.
+func (me *RollingQuantile[T]) rebalance() {
+	want := 0
+	if me.count > 0 {
+		want = int(math.Floor(me.p/100*float64(me.count-1))) + 1
+	}
+	for me.lower.len() > want {
+		me.higher.push(me.lower.remove(0))
+	}
+	for me.lower.len() < want {
+		me.lower.push(me.higher.remove(0))
+	}
+}
+
+// Value returns the percentile of the elements, or NaN if the window is empty.
+func (me *RollingQuantile[T]) Value() float64 {
+	return percentile(me.count, func(k int) (T, bool) {
+		if k < me.lower.len() {
+			return me.lower.top(), true
+		}
+		return me.higher.top(), true
+	}, me.p, me.interpolation)
+}
+
+func (me *RollingQuantile[T]) Count() int {
+	return me.count
+}
+
+func (me *RollingQuantile[T]) Clear() {
+	me.head = 0
+	me.count = 0
+	me.lower.slots = me.lower.slots[:0]
+	me.higher.slots = me.higher.slots[:0]
+}
+
+func (me *RollingQuantile[T]) MaxSize() int {
+	return len(me.vals)
+}
+
+// Full returns true if the window's max size is reached.
+func (me *RollingQuantile[T]) Full() bool {
+	return me.count >= len(me.vals)
+}
+
+// Filled is the same as Full except it returns false if the window's size is 0
+func (me *RollingQuantile[T]) Filled() bool {
+	return me.count != 0 && me.Full()
+}
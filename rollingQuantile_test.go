@@ -0,0 +1,138 @@
+package quantainer
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func ExampleNewRollingMedian() {
+	m := NewRollingMedian[float64](4)
+	for _, v := range []float64{5, 1, 4, 2, 8} {
+		m.AddLast(v)
+		fmt.Print(m.Value(), " ")
+	}
+	fmt.Println()
+	// Output:
+	// 5 3 4 3 3
+}
+
+func TestRollingQuantile_MatchesSortedRingBuffer(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, size := range []int{1, 2, 3, 10, 101} {
+		for _, p := range []float64{0, 10, 25, 50, 90, 100} {
+			for _, interpolation := range []Interpolation{InterpolationLinear, InterpolationLower, InterpolationHigher, InterpolationNearest, InterpolationMidpoint} {
+				q := NewRollingQuantile[int](size, p, interpolation)
+				ref := NewSortedRingBuffer[int](size)
+				for i := 0; i < 300; i++ {
+					v := r.Intn(50) // many duplicates
+					q.AddLast(v)
+					ref.AddLast(v)
+					if i%7 == 3 {
+						x, _ := q.PopFirst()
+						if y := ref.PopFirst(); x != *y {
+							t.Fatalf("PopFirst got %v want %v", x, *y)
+						}
+					}
+					got, want := q.Value(), SortedRingBufferPercentile(ref, p, interpolation)
+					if got != want && !(math.IsNaN(got) && math.IsNaN(want)) {
+						t.Fatalf("size %d p %v interpolation %d step %d: got %v want %v", size, p, interpolation, i, got, want)
+					}
+					if q.Count() != ref.Count() || q.Full() != ref.Full() || q.Filled() != ref.Filled() {
+						t.Fatalf("size %d step %d: count %d want %d", size, i, q.Count(), ref.Count())
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRollingQuantile_Empty(t *testing.T) {
+	q := NewRollingMedian[float64](0)
+	q.AddLast(1)
+	if q.Count() != 0 || q.Filled() || !math.IsNaN(q.Value()) || q.MaxSize() != 0 {
+		t.Fatal("Expected an empty window of size 0")
+	}
+
+	q = NewRollingMedian[float64](3)
+	if _, ok := q.PopFirst(); ok || !math.IsNaN(q.Value()) {
+		t.Fatal("Expected nothing from an empty window")
+	}
+	q.AddLast(1)
+	q.AddLast(2)
+	q.Clear()
+	if q.Count() != 0 || !math.IsNaN(q.Value()) {
+		t.Fatal("Expected an empty window after Clear")
+	}
+	q.AddLast(7)
+	if q.Value() != 7 {
+		t.Fatalf("Expected 7 after Clear, got %v", q.Value())
+	}
+}
+
+func TestRollingQuantile_InvalidPercentile(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected a panic for a percentile out of range")
+		}
+	}()
+	NewRollingQuantile[float64](10, 101, InterpolationLinear)
+}
+
+func TestRollingQuantile_NoAllocs(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	q := NewRollingQuantile[float64](1000, 95, InterpolationLinear)
+	allocs := testing.AllocsPerRun(10000, func() {
+		q.AddLast(r.Float64())
+		q.Value()
+	})
+	if allocs != 0 {
+		t.Fatalf("Expected no allocations, got %v per update", allocs)
+	}
+}
+
+func benchmarkValues(n int) []float64 {
+	r := rand.New(rand.NewSource(1))
+	result := make([]float64, n)
+	for i := range result {
+		result[i] = r.Float64()
+	}
+	return result
+}
+
+func BenchmarkRollingMedian(b *testing.B) {
+	values := benchmarkValues(4096)
+	m := NewRollingMedian[float64](1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.AddLast(values[i&4095])
+		m.Value()
+	}
+}
+
+func BenchmarkSortedRingBuffer_Median(b *testing.B) {
+	values := benchmarkValues(4096)
+	s := NewSortedRingBuffer[float64](1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.AddLast(values[i&4095])
+		SortedRingBufferMedian(s)
+	}
+}
+
+func BenchmarkSortedRingBuffer_SortedSliceMedian(b *testing.B) {
+	values := benchmarkValues(4096)
+	s := NewSortedRingBuffer[float64](1000)
+	var sorted []float64
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.AddLast(values[i&4095])
+		sorted = s.SortedSlice(sorted)
+		n := len(sorted)
+		_ = (sorted[(n-1)/2] + sorted[n/2]) / 2
+	}
+}
@@ -0,0 +1,202 @@
+package quantainer
+
+import (
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+type (
+	// Number is the set of element types the numeric rolling windows accept.
+	Number interface {
+		constraints.Integer | constraints.Float
+	}
+
+	// kahanSum is a Kahan-compensated running sum.
+	kahanSum struct {
+		sum, c float64
+	}
+
+	// RollingStats is a RingBuffer that maintains sum, sum of squares, mean and variance
+	// of its elements in O(1) per update.
+	// Mean and variance use Welford's update and the sums are Kahan-compensated,
+	// so long-running float windows do not drift.
+	RollingStats[T Number] struct {
+		rb       RingBuffer[T]
+		sum      kahanSum
+		sumSq    kahanSum
+		mean, m2 float64
+		maxSize  func() int
+		size     int // maxSize as last read, so rb evicts exactly what the statistics remove
+	}
+)
+
+func (me *kahanSum) add(v float64) {
+	y := v - me.c
+	t := me.sum + y
+	me.c = (t - me.sum) - y
+	me.sum = t
+}
+
+func (me *kahanSum) reset() {
+	me.sum = 0
+	me.c = 0
+}
+
+func NewRollingStats[T Number](size int) *RollingStats[T] {
+	return &RollingStats[T]{
+		rb: NewRingBuffer[T](size),
+	}
+}
+
+// NewRollingStatsConfigurable creates a new rolling window with a configurable maximum size.
+// If maxSize shrinks at runtime, the oldest elements are removed from the statistics on the next AddLast.
+func NewRollingStatsConfigurable[T Number](maxSize func() int) *RollingStats[T] {
+	result := &RollingStats[T]{maxSize: maxSize, size: maxSize()}
+	result.rb = *NewRingBufferConfigurable[T](func() int {
+		return result.size
+	})
+	return result
+}
+
+func (me *RollingStats[T]) add(v T) {
+	x := float64(v)
+	me.sum.add(x)
+	me.sumSq.add(x * x)
+	n := float64(me.rb.count)
+	delta := x - me.mean
+	me.mean += delta / n
+	me.m2 += delta * (x - me.mean)
+}
+
+func (me *RollingStats[T]) remove(v T) {
+	if me.rb.count == 0 {
+		me.reset()
+		return
+	}
+	x := float64(v)
+	me.sum.add(-x)
+	me.sumSq.add(-x * x)
+	n := float64(me.rb.count)
+	delta := x - me.mean
+	me.mean -= delta / n
+	me.m2 -= delta * (x - me.mean)
+	if me.m2 < 0 {
+		me.m2 = 0
+	}
+}
+
+func (me *RollingStats[T]) reset() {
+	me.sum.reset()
+	me.sumSq.reset()
+	me.mean = 0
+	me.m2 = 0
+}
+
+// AddLast adds an element to the end of the window.
+// If the window is full, the oldest element is evicted first.
+func (me *RollingStats[T]) AddLast(v T) {
+	if me.maxSize != nil {
+		me.size = me.maxSize() // read once, so rb cannot drop elements the statistics still count
+	}
+	size := me.rb.maxSize()
+	for me.rb.count > 0 && me.rb.count >= size {
+		me.PopFirst()
+	}
+	if size == 0 {
+		return
+	}
+	me.rb.AddLast(v)
+	me.add(v)
+}
+
+func (me *RollingStats[T]) PopFirst() (result *T) {
+	result = me.rb.PopFirst()
+	if result != nil {
+		me.remove(*result)
+	}
+	return
+}
+
+// Sum returns the sum of the elements in the window.
+func (me *RollingStats[T]) Sum() float64 {
+	return me.sum.sum
+}
+
+// SumSq returns the sum of squares of the elements in the window.
+func (me *RollingStats[T]) SumSq() float64 {
+	return me.sumSq.sum
+}
+
+// Mean returns the arithmetic mean, or NaN if the window is empty.
+func (me *RollingStats[T]) Mean() float64 {
+	if me.rb.count == 0 {
+		return math.NaN()
+	}
+	return me.mean
+}
+
+// Variance returns the population variance, or NaN if the window is empty.
+func (me *RollingStats[T]) Variance() float64 {
+	if me.rb.count == 0 {
+		return math.NaN()
+	}
+	return me.m2 / float64(me.rb.count)
+}
+
+// SampleVariance returns the unbiased sample variance, or NaN if the window has less than 2 elements.
+func (me *RollingStats[T]) SampleVariance() float64 {
+	if me.rb.count < 2 {
+		return math.NaN()
+	}
+	return me.m2 / float64(me.rb.count-1)
+}
+
+// StdDev returns the population standard deviation.
+func (me *RollingStats[T]) StdDev() float64 {
+	return math.Sqrt(me.Variance())
+}
+
+// SampleStdDev returns the sample standard deviation.
+func (me *RollingStats[T]) SampleStdDev() float64 {
+	return math.Sqrt(me.SampleVariance())
+}
+
+func (me *RollingStats[T]) ToSlice() []T {
+	return me.rb.ToSlice()
+}
+
+func (me *RollingStats[T]) Count() int {
+	return me.rb.count
+}
+
+func (me *RollingStats[T]) First() *T {
+	return me.rb.First()
+}
+
+func (me *RollingStats[T]) Last() *T {
+	return me.rb.Last()
+}
+
+func (me *RollingStats[T]) At(i int) *T {
+	return me.rb.At(i)
+}
+
+func (me *RollingStats[T]) Clear() {
+	me.rb.Clear()
+	me.reset()
+}
+
+func (me *RollingStats[T]) MaxSize() int {
+	return me.rb.maxSize()
+}
+
+// Full returns true if the window's max size is reached.
+func (me *RollingStats[T]) Full() bool {
+	return me.rb.Full()
+}
+
+// Filled is the same as Full except it returns false if the window's size is 0
+func (me *RollingStats[T]) Filled() bool {
+	return me.rb.Filled()
+}
@@ -0,0 +1,148 @@
+package quantainer
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func ExampleRollingStats_Mean() {
+	s := NewRollingStats[int](3)
+	s.AddLast(1)
+	s.AddLast(2)
+	s.AddLast(3)
+	s.AddLast(4)
+	fmt.Println(s.Sum(), s.Mean(), s.Variance())
+	// Output:
+	// 9 3 0.6666666666666666
+}
+
+// naive mean and population variance for reference
+func naiveMeanVar(s []float64) (mean, variance float64) {
+	for _, v := range s {
+		mean += v
+	}
+	mean /= float64(len(s))
+	for _, v := range s {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(s))
+	return
+}
+
+func assertRollingStats(t *testing.T, s *RollingStats[float64], step int) {
+	t.Helper()
+	values := s.ToSlice()
+	if len(values) == 0 {
+		if !math.IsNaN(s.Mean()) || s.Sum() != 0 {
+			t.Fatalf("step %d: empty window want NaN mean and 0 sum, got %v %v", step, s.Mean(), s.Sum())
+		}
+		return
+	}
+	mean, variance := naiveMeanVar(values)
+	sum := mean * float64(len(values))
+	if math.Abs(s.Sum()-sum) > 1e-6 {
+		t.Fatalf("step %d: sum got %v want %v", step, s.Sum(), sum)
+	}
+	if math.Abs(s.Mean()-mean) > 1e-9 {
+		t.Fatalf("step %d: mean got %v want %v", step, s.Mean(), mean)
+	}
+	if math.Abs(s.Variance()-variance) > 1e-6 {
+		t.Fatalf("step %d: variance got %v want %v", step, s.Variance(), variance)
+	}
+}
+
+func TestRollingStats_MatchesNaive(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := NewRollingStats[float64](20)
+	for i := 0; i < 10000; i++ {
+		s.AddLast(r.NormFloat64()*10 + 1000)
+		assertRollingStats(t, s, i)
+	}
+}
+
+// A window of large, nearly equal values is the classic case where naive sum-of-squares drifts.
+func TestRollingStats_NoDrift(t *testing.T) {
+	s := NewRollingStats[float64](10)
+	for i := 0; i < 1000000; i++ {
+		s.AddLast(1e9 + float64(i%10))
+	}
+	if got, want := s.Mean(), 1e9+4.5; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("mean got %v want %v", got, want)
+	}
+	if got, want := s.Variance(), 8.25; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("variance got %v want %v", got, want)
+	}
+}
+
+func TestRollingStats_Integer(t *testing.T) {
+	s := NewRollingStats[int64](4)
+	for _, v := range []int64{2, 4, 4, 4, 5, 5, 7, 9} {
+		s.AddLast(v)
+	}
+	// window [5 5 7 9]
+	if s.Sum() != 26 || s.Mean() != 6.5 {
+		t.Fatalf("sum/mean got %v/%v want 26/6.5", s.Sum(), s.Mean())
+	}
+	if got := s.SampleVariance(); math.Abs(got-11.0/3) > 1e-12 {
+		t.Fatalf("sample variance got %v want %v", got, 11.0/3)
+	}
+	if !s.Filled() {
+		t.Fatalf("Filled want true")
+	}
+}
+
+func TestRollingStats_Resize(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	size := 5
+	s := NewRollingStatsConfigurable[float64](func() int { return size })
+	for i := 0; i < 2000; i++ {
+		if i%37 == 0 {
+			size = r.Intn(12)
+		}
+		s.AddLast(r.Float64() * 100)
+		if s.Count() > size {
+			t.Fatalf("step %d: count %d exceeds size %d", i, s.Count(), size)
+		}
+		assertRollingStats(t, s, i)
+	}
+}
+
+func TestRollingStats_PopFirstAndClear(t *testing.T) {
+	s := NewRollingStats[float64](3)
+	s.AddLast(1)
+	s.AddLast(2)
+	s.AddLast(6)
+	if v := s.PopFirst(); v == nil || *v != 1 {
+		t.Fatalf("PopFirst want 1 got %v", v)
+	}
+	assertRollingStats(t, s, 0)
+	s.PopFirst()
+	s.PopFirst()
+	if s.PopFirst() != nil {
+		t.Fatalf("PopFirst on empty want nil")
+	}
+	assertRollingStats(t, s, 1)
+
+	s.AddLast(3)
+	s.Clear()
+	if s.Count() != 0 || s.Sum() != 0 || s.SumSq() != 0 || !math.IsNaN(s.Mean()) {
+		t.Fatalf("Clear should reset statistics")
+	}
+}
+
+// maxSize is read once per AddLast, so an irregular sequence cannot make rb drop elements the statistics still count.
+func TestRollingStats_ConfigurableReadOnce(t *testing.T) {
+	sizes := []int{5, 3, 4, 2, 6}
+	calls := 0
+	s := NewRollingStatsConfigurable[float64](func() int {
+		result := sizes[calls%len(sizes)]
+		calls++
+		return result
+	})
+	for i := 0; i < 50; i++ {
+		s.AddLast(float64(i % 7))
+		assertRollingStats(t, s, i)
+	}
+}
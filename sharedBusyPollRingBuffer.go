@@ -0,0 +1,177 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package quantainer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+// ErrSharedBufferMismatch is returned when attaching to a shared ring buffer whose layout does not match
+// the reader's: a different layout version, element size or file size, or a file that is not a ring buffer.
+var ErrSharedBufferMismatch = errors.New("quantainer: shared ring buffer layout mismatch")
+
+const (
+	sharedBusyPollMagic      = 0x42525042544e5451 // "QTNTBPRB" in little endian
+	sharedBusyPollVersion    = 1
+	sharedBusyPollHeaderSize = 128
+)
+
+type (
+	// SharedBusyPollRingBuffer is a BusyPollRingBuffer whose items, version stamps and sequence numbers live
+	// in a memory mapped file, typically under /dev/shm, so readers in other processes can attach to it
+	// with AttachBusyPollRingBufferReader.
+	// T must have a fixed size that is a multiple of 4 bytes, padded if needed, and contain no pointers,
+	// strings, slices, maps, interfaces, channels or funcs, so it is copied with atomic word loads and stores.
+	SharedBusyPollRingBuffer[T any] struct {
+		BusyPollRingBuffer[T]
+		mem []byte
+	}
+
+	// SharedBusyPollRingBufferReader is a reader attached to a SharedBusyPollRingBuffer by path.
+	// The writer cannot wake readers of other processes, so ReadWait keeps yielding instead of parking.
+	SharedBusyPollRingBufferReader[T any] struct {
+		*BusyPollRingBufferReader[T]
+		mem []byte
+	}
+
+	// sharedBusyPollHeader is the layout of the start of the file. The data follows at sharedBusyPollHeaderSize:
+	// capacity version stamps, then capacity items aligned to 64 bytes.
+	sharedBusyPollHeader struct {
+		magic    atomic.Uint64 // stored last, once the rest of the header is initialized
+		version  uint32
+		elemSize uint32
+		capacity uint64
+		_        [40]byte
+		busyPollHeader
+	}
+)
+
+// CreateSharedBusyPollRingBuffer creates a ring buffer of size items backed by the file at path.
+// An existing file is replaced; readers still attached to it keep the old buffer.
+func CreateSharedBusyPollRingBuffer[T any](path string, size int) (result *SharedBusyPollRingBuffer[T], err error) {
+	if err = checkSharedType[T](); err != nil {
+		return
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("quantainer: invalid shared ring buffer size %d", size)
+	}
+	if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	length := sharedBusyPollLength[T](size)
+	if err = f.Truncate(int64(length)); err != nil {
+		return
+	}
+	mem, err := syscall.Mmap(int(f.Fd()), 0, length, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return
+	}
+
+	hdr := (*sharedBusyPollHeader)(unsafe.Pointer(&mem[0]))
+	hdr.version = sharedBusyPollVersion
+	hdr.elemSize = uint32(unsafe.Sizeof(*new(T)))
+	hdr.capacity = uint64(size)
+	hdr.magic.Store(sharedBusyPollMagic)
+
+	result = &SharedBusyPollRingBuffer[T]{mem: mem}
+	result.attach(mem, size)
+	return
+}
+
+// Close unmaps the buffer. It must not be used afterwards, the file is left in place.
+func (me *SharedBusyPollRingBuffer[T]) Close() error {
+	return syscall.Munmap(me.mem)
+}
+
+// AttachBusyPollRingBufferReader attaches a reader to the SharedBusyPollRingBuffer at path, usually created by
+// another process. It returns an error wrapping ErrSharedBufferMismatch if the buffer was created with
+// a different layout version or element size.
+func AttachBusyPollRingBufferReader[T any](path string) (result *SharedBusyPollRingBufferReader[T], err error) {
+	if err = checkSharedType[T](); err != nil {
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return
+	}
+	if info.Size() < sharedBusyPollHeaderSize {
+		return nil, fmt.Errorf("%w: %s is too small", ErrSharedBufferMismatch, path)
+	}
+	mem, err := syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return
+	}
+
+	hdr := (*sharedBusyPollHeader)(unsafe.Pointer(&mem[0]))
+	elemSize := uint32(unsafe.Sizeof(*new(T)))
+	switch {
+	case hdr.magic.Load() != sharedBusyPollMagic:
+		err = fmt.Errorf("%w: %s is not a ring buffer", ErrSharedBufferMismatch, path)
+	case hdr.version != sharedBusyPollVersion:
+		err = fmt.Errorf("%w: layout version %d, want %d", ErrSharedBufferMismatch, hdr.version, sharedBusyPollVersion)
+	case hdr.elemSize != elemSize:
+		err = fmt.Errorf("%w: element size %d, want %d", ErrSharedBufferMismatch, hdr.elemSize, elemSize)
+	case hdr.capacity == 0 || hdr.capacity > uint64(len(mem)) || sharedBusyPollLength[T](int(hdr.capacity)) != len(mem):
+		err = fmt.Errorf("%w: capacity %d does not match file size %d", ErrSharedBufferMismatch, hdr.capacity, len(mem))
+	}
+	if err != nil {
+		syscall.Munmap(mem)
+		return
+	}
+
+	ring := &busyPollRing[T]{remote: true}
+	ring.attach(mem, int(hdr.capacity))
+	return &SharedBusyPollRingBufferReader[T]{
+		BusyPollRingBufferReader: ring.Reader(),
+		mem:                      mem,
+	}, nil
+}
+
+// Close unmaps the buffer. The reader must not be used afterwards.
+func (me *SharedBusyPollRingBufferReader[T]) Close() error {
+	return syscall.Munmap(me.mem)
+}
+
+// attach points the ring at the header, version stamps and items in mem.
+func (me *busyPollRing[T]) attach(mem []byte, size int) {
+	me.busyPollHeader = &(*sharedBusyPollHeader)(unsafe.Pointer(&mem[0])).busyPollHeader
+	me.versions = unsafe.Slice((*atomic.Uint64)(unsafe.Pointer(&mem[sharedBusyPollHeaderSize])), size)
+	me.data = unsafe.Slice((*T)(unsafe.Pointer(&mem[sharedBusyPollDataOffset(size)])), size)
+	me.initCopy()
+}
+
+// checkSharedType returns an error if T cannot be shared between processes: the slot locks that guard
+// other types are private to each process.
+func checkSharedType[T any]() error {
+	if err := checkRawType[T](); err != nil {
+		return err
+	}
+	if atomicWord[T]() == 0 {
+		return fmt.Errorf("quantainer: the size of %T is not a multiple of 4 bytes", *new(T))
+	}
+	return nil
+}
+
+func sharedBusyPollDataOffset(size int) int {
+	return (sharedBusyPollHeaderSize + 8*size + 63) &^ 63
+}
+
+func sharedBusyPollLength[T any](size int) int {
+	return sharedBusyPollDataOffset(size) + size*int(unsafe.Sizeof(*new(T)))
+}
@@ -0,0 +1,143 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package quantainer
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type sharedTick struct {
+	Seq   uint64
+	Price float64
+	Qty   int32
+	Side  [4]byte
+}
+
+func TestSharedBusyPollRingBuffer_Attach(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ticks")
+	buf, err := CreateSharedBusyPollRingBuffer[sharedTick](path, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer buf.Close()
+	buf.Write(sharedTick{Seq: 0, Price: 1.5})
+
+	reader, err := AttachBusyPollRingBufferReader[sharedTick](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	buf.WriteMany([]sharedTick{{Seq: 1, Price: 2.5}, {Seq: 2, Price: 3.5}})
+
+	// the reader starts from the last write, like Reader
+	var val sharedTick
+	for _, exp := range []uint64{0, 1, 2} {
+		if seq, _, ok := reader.ReadSeq(&val); !ok || seq != exp || val.Seq != exp {
+			t.Fatalf("Expected seq %d, got seq=%d ok=%v val=%+v", exp, seq, ok, val)
+		}
+	}
+	if reader.Read(&val) {
+		t.Fatal("Expected no more data")
+	}
+	if buf.Seq() != 3 || reader.buf.Seq() != 3 || reader.buf.Len() != 8 {
+		t.Fatalf("Seq/Len want 3/8 got %d/%d", reader.buf.Seq(), reader.buf.Len())
+	}
+}
+
+func TestSharedBusyPollRingBuffer_Mismatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ticks")
+	buf, err := CreateSharedBusyPollRingBuffer[sharedTick](path, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer buf.Close()
+
+	if _, err := AttachBusyPollRingBufferReader[int64](path); !errors.Is(err, ErrSharedBufferMismatch) {
+		t.Fatalf("Expected ErrSharedBufferMismatch for element size, got %v", err)
+	}
+	buf.mem[8]++ // layout version
+	if _, err := AttachBusyPollRingBufferReader[sharedTick](path); !errors.Is(err, ErrSharedBufferMismatch) {
+		t.Fatalf("Expected ErrSharedBufferMismatch for version, got %v", err)
+	}
+	buf.mem[8]--
+
+	other := filepath.Join(dir, "other")
+	if err := os.WriteFile(other, make([]byte, 4096), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AttachBusyPollRingBufferReader[sharedTick](other); !errors.Is(err, ErrSharedBufferMismatch) {
+		t.Fatalf("Expected ErrSharedBufferMismatch for a foreign file, got %v", err)
+	}
+}
+
+func TestSharedBusyPollRingBuffer_RejectsPointers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ticks")
+	if _, err := CreateSharedBusyPollRingBuffer[string](path, 8); err == nil {
+		t.Fatal("Expected strings to be rejected")
+	}
+	if _, err := CreateSharedBusyPollRingBuffer[struct{ P *int }](path, 8); err == nil {
+		t.Fatal("Expected pointers to be rejected")
+	}
+	if _, err := AttachBusyPollRingBufferReader[[]int](path); err == nil {
+		t.Fatal("Expected slices to be rejected")
+	}
+	// items that cannot be copied by atomic words would need locks shared between the processes
+	if _, err := CreateSharedBusyPollRingBuffer[[3]byte](path, 8); err == nil {
+		t.Fatal("Expected a size that is not a multiple of 4 to be rejected")
+	}
+}
+
+const sharedTestPathEnv = "QUANTAINER_SHARED_RING_PATH"
+
+// Runs in a child process started by TestSharedBusyPollRingBuffer_CrossProcess.
+func TestSharedBusyPollRingBuffer_Child(t *testing.T) {
+	path := os.Getenv(sharedTestPathEnv)
+	if path == "" {
+		t.Skip("only run by TestSharedBusyPollRingBuffer_CrossProcess")
+	}
+	reader, err := AttachBusyPollRingBufferReader[sharedTick](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	var val sharedTick
+	for i := uint64(0); i < 100; i++ {
+		deadline := time.Now().Add(5 * time.Second)
+		for !reader.Read(&val) {
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out waiting for item %d", i)
+			}
+		}
+		if val.Seq != i || val.Price != float64(i)/2 {
+			t.Fatalf("Expected item %d, got %+v", i, val)
+		}
+	}
+}
+
+func TestSharedBusyPollRingBuffer_CrossProcess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ticks")
+	buf, err := CreateSharedBusyPollRingBuffer[sharedTick](path, 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer buf.Close()
+	ticks := make([]sharedTick, 100)
+	for i := range ticks {
+		ticks[i] = sharedTick{Seq: uint64(i), Price: float64(i) / 2}
+	}
+	buf.WriteMany(ticks)
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSharedBusyPollRingBuffer_Child$", "-test.count=1")
+	cmd.Env = append(os.Environ(), sharedTestPathEnv+"="+path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("child process failed: %v\n%s", err, out)
+	}
+}
@@ -7,9 +7,13 @@ import (
 
 /* SortedFixedList */
 
+// SortedFixedList is a FixedList that also keeps its elements sorted, for Select and Rank in O(log n).
+// Percentile and Median need numeric elements, and Go methods cannot constrain T further than the type does,
+// so they are the functions SortedFixedListPercentile and SortedFixedListMedian rather than methods.
 type SortedFixedList[T constraints.Ordered] struct {
 	*FixedList[T]
 	*treemap.TreeMap[T, int]
+	os orderStatTree[T] // same content as TreeMap, with subtree counts for order statistics
 }
 
 func NewSortedFixedList[T constraints.Ordered](size int) *SortedFixedList[T] {
@@ -30,7 +34,7 @@ func (me *SortedFixedList[T]) AddFirst(v T) {
 	me.l.AddFirst(v)
 	me.addToTreeMap(v)
 	for me.l.count > me.maxSize() {
-		me.PopLast()
+		me.evict(me.PopLast())
 	}
 }
 
@@ -38,11 +42,12 @@ func (me *SortedFixedList[T]) AddLast(v T) {
 	me.l.AddLast(v)
 	me.addToTreeMap(v)
 	for me.l.count > me.maxSize() {
-		me.PopFirst()
+		me.evict(me.PopFirst())
 	}
 }
 
 func (me *SortedFixedList[T]) removeFromTreeMap(v T) {
+	me.os.Remove(v)
 	ref, ok := me.TreeMap.GetRef(v)
 	if ok {
 		r := *ref
@@ -56,6 +61,7 @@ func (me *SortedFixedList[T]) removeFromTreeMap(v T) {
 }
 
 func (me *SortedFixedList[T]) addToTreeMap(v T) {
+	me.os.Add(v)
 	ref, ok := me.TreeMap.GetRef(v)
 	if !ok {
 		me.TreeMap.Set(v, 1)
@@ -105,6 +111,28 @@ func (me *SortedFixedList[T]) SortedSlice() []T {
 	return result
 }
 
+// Select returns the k-th smallest element (0-based) in O(log n).
+// ok is false if k is out of range.
+func (me *SortedFixedList[T]) Select(k int) (result T, ok bool) {
+	return me.os.Select(k)
+}
+
+// Rank returns the number of elements strictly less than v in O(log n).
+func (me *SortedFixedList[T]) Rank(v T) int {
+	return me.os.Rank(v)
+}
+
+// SortedFixedListPercentile returns the p-th percentile (0 <= p <= 100) of the elements in O(log n),
+// or NaN if the list is empty.
+func SortedFixedListPercentile[T Number](me *SortedFixedList[T], p float64, interpolation Interpolation) float64 {
+	return percentile(me.l.count, me.os.Select, p, interpolation)
+}
+
+// SortedFixedListMedian returns the median of the elements, or NaN if the list is empty.
+func SortedFixedListMedian[T Number](me *SortedFixedList[T]) float64 {
+	return percentile(me.l.count, me.os.Select, 50, InterpolationLinear)
+}
+
 func (me *SortedFixedList[T]) Count() int {
 	return me.l.count
 }
@@ -120,6 +148,7 @@ func (me *SortedFixedList[T]) Last() *Node[T] {
 func (me *SortedFixedList[T]) Clear() {
 	me.l.Clear()
 	me.TreeMap.Clear()
+	me.os.Clear()
 }
 
 func (me *SortedFixedList[T]) MaxSize() int {
@@ -73,3 +73,63 @@ func TestSortedFixedList_Filled(t *testing.T) {
 		t.Fatalf("at capacity Filled want true")
 	}
 }
+
+func TestSortedFixedList_OrderStatistics(t *testing.T) {
+	l := NewSortedFixedList[int](4)
+	for _, v := range []int{2, 2, 5, 1, 2, 4} {
+		l.AddLast(v)
+	}
+	// list is [5 1 2 4]
+	l.Remove(l.First())
+	// list is [1 2 4]
+	if v, ok := l.Select(2); !ok || v != 4 {
+		t.Fatalf("Select(2) got %v want 4", v)
+	}
+	if _, ok := l.Select(3); ok {
+		t.Fatalf("Select(3) want ok=false")
+	}
+	if got := l.Rank(4); got != 2 {
+		t.Fatalf("Rank(4) got %d want 2", got)
+	}
+	// the embedded TreeMap counts the same elements
+	if ref, ok := l.TreeMap.GetRef(2); !ok || *ref != 1 {
+		t.Fatalf("TreeMap count of 2 got %v want 1", ref)
+	}
+	if _, ok := l.TreeMap.GetRef(5); ok {
+		t.Fatalf("TreeMap still has the removed 5")
+	}
+	if got := SortedFixedListMedian(l); got != 2 {
+		t.Fatalf("Median got %v want 2", got)
+	}
+	if got := SortedFixedListPercentile(l, 75, InterpolationMidpoint); got != 3 {
+		t.Fatalf("Percentile(75) got %v want 3", got)
+	}
+	l.Clear()
+	if _, ok := l.Select(0); ok {
+		t.Fatalf("Select after Clear want ok=false")
+	}
+}
+
+func TestSortedFixedList_OnEvict(t *testing.T) {
+	l := NewSortedFixedList[int](3)
+	var evicted []int
+	l.OnEvict(func(v int) { evicted = append(evicted, v) })
+	for i := 1; i <= 5; i++ {
+		l.AddLast(i)
+	}
+	l.AddFirst(0)
+	if fmt.Sprint(evicted) != "[1 2 5]" {
+		t.Fatalf("evicted want [1 2 5] got %v", evicted)
+	}
+	if fmt.Sprint(l.SortedSlice()) != "[0 3 4]" {
+		t.Fatalf("Expected the evicted values to leave the sorted view, got %v", l.SortedSlice())
+	}
+	if v, _ := l.Select(2); v != 4 {
+		t.Fatalf("Select(2) want 4 got %v", v)
+	}
+
+	l.PopFirst()
+	if len(evicted) != 3 {
+		t.Fatalf("Expected PopFirst not to call OnEvict, got %v", evicted)
+	}
+}
@@ -1,74 +1,57 @@
 package quantainer
 
-import (
-	"github.com/szmcdull/treemap/v2"
-	"golang.org/x/exp/constraints"
-)
+import "golang.org/x/exp/constraints"
 
 /* SortedRingBuffer */
 
+// SortedRingBuffer is a RingBuffer that also keeps its elements sorted, for Select and Rank in O(log n).
+// Percentile and Median need numeric elements, and Go methods cannot constrain T further than the type does,
+// so they are the functions SortedRingBufferPercentile and SortedRingBufferMedian rather than methods.
 type SortedRingBuffer[T constraints.Ordered] struct {
 	rb RingBuffer[T]
-	m  *treemap.TreeMap[T, int]
+	m  orderStatTree[T]
 }
 
 func NewSortedRingBuffer[T constraints.Ordered](size int) *SortedRingBuffer[T] {
 	return &SortedRingBuffer[T]{
 		rb: NewRingBuffer[T](size),
-		m:  treemap.New[T, int](),
 	}
 }
 
-// Cannot change size at runtime. Because rb.AddLast will potentially drop items if size decrease,
-// but currently no way to sync m with rb in such situation.
-//
-// func NewSortedRingBufferConfigurable[T constraints.Ordered](configLoader func() int) *SortedRingBuffer[T] {
-// 	return &SortedRingBuffer[T]{
-// 		rb: NewRingBufferConfigurable[T](configLoader),
-// 		m:  treemap.New[T, int](),
-// 	}
-// }
-
-func (me *SortedRingBuffer[T]) AddLast(v T) {
-	if me.rb.Full() {
-		me.PopFirst()
-	}
-	countBefore := me.rb.count
-	me.rb.AddLast(v)
-	if me.rb.count == countBefore {
-		return
+// NewSortedRingBufferConfigurable creates a new sorted ring buffer with a configurable maximum size.
+// If maxSize is changed at runtime, the buffer will not resize until you add an element.
+func NewSortedRingBufferConfigurable[T constraints.Ordered](maxSize func() int) *SortedRingBuffer[T] {
+	return &SortedRingBuffer[T]{
+		rb: *NewRingBufferConfigurable[T](maxSize),
 	}
-	me.addToTreeMap(v)
-}
-
-func (me *SortedRingBuffer[T]) removeFromTreeMap(v T) {
-	ref, ok := me.m.GetRef(v)
-	if ok {
-		r := *ref
-		r--
-		if r == 0 {
-			me.m.Del(v)
-		} else {
-			*ref = r
-		}
+}
+
+// evict removes an element dropped by rb from m.
+func (me *SortedRingBuffer[T]) evict(v T) {
+	me.m.Remove(v)
+	if me.rb.onEvict != nil {
+		me.rb.onEvict(v)
 	}
 }
 
-func (me *SortedRingBuffer[T]) addToTreeMap(v T) {
-	ref, ok := me.m.GetRef(v)
-	if !ok {
-		me.m.Set(v, 1)
-		return
+func (me *SortedRingBuffer[T]) AddLast(v T) {
+	if me.rb.addLast(v, me.evict) {
+		me.m.Add(v)
+	} else if me.rb.onEvict != nil {
+		me.rb.onEvict(v)
 	}
-	r := *ref
-	r++
-	*ref = r
+}
+
+// OnEvict sets fn to be called with every element AddLast drops: the oldest element when the buffer is full,
+// the oldest elements that no longer fit when maxSize shrank, and the added element itself if maxSize is 0.
+func (me *SortedRingBuffer[T]) OnEvict(fn func(v T)) {
+	me.rb.onEvict = fn
 }
 
 func (me *SortedRingBuffer[T]) PopFirst() *T {
 	result := me.rb.PopFirst()
 	if result != nil {
-		me.removeFromTreeMap(*result)
+		me.m.Remove(*result)
 	}
 	return result
 }
@@ -85,17 +68,37 @@ func (me *SortedRingBuffer[T]) SortedSlice(cachedSlice []T) []T {
 		result = make([]T, me.rb.count)
 	}
 	ii := 0
-	for i := me.m.Iterator(); i.Valid(); i.Next() {
-		count := i.Value()
-		key := i.Key()
+	me.m.Ascend(func(key T, count int) {
 		for j := 0; j < count; j++ {
 			result[ii] = key
 			ii++
 		}
-	}
+	})
 	return result
 }
 
+// Select returns the k-th smallest element (0-based) in O(log n).
+// ok is false if k is out of range.
+func (me *SortedRingBuffer[T]) Select(k int) (result T, ok bool) {
+	return me.m.Select(k)
+}
+
+// Rank returns the number of elements strictly less than v in O(log n).
+func (me *SortedRingBuffer[T]) Rank(v T) int {
+	return me.m.Rank(v)
+}
+
+// SortedRingBufferPercentile returns the p-th percentile (0 <= p <= 100) of the elements in O(log n),
+// or NaN if the buffer is empty.
+func SortedRingBufferPercentile[T Number](me *SortedRingBuffer[T], p float64, interpolation Interpolation) float64 {
+	return percentile(me.rb.count, me.m.Select, p, interpolation)
+}
+
+// SortedRingBufferMedian returns the median of the elements, or NaN if the buffer is empty.
+func SortedRingBufferMedian[T Number](me *SortedRingBuffer[T]) float64 {
+	return percentile(me.rb.count, me.m.Select, 50, InterpolationLinear)
+}
+
 func (me *SortedRingBuffer[T]) Count() int {
 	return me.rb.count
 }
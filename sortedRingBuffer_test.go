@@ -2,7 +2,10 @@ package quantainer
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
 	"reflect"
+	"sort"
 	"testing"
 )
 
@@ -36,7 +39,7 @@ func ExampleSortedRingBuffer_SortedSlice() {
 	// 3
 }
 
-// Test duplicates handling and TreeMap count sync when items are evicted
+// Test duplicates handling and order-statistic tree count sync when items are evicted
 func TestSortedRingBuffer_DuplicatesAndEviction(t *testing.T) {
 	l := NewSortedRingBuffer[int](5)
 	l.AddLast(2)
@@ -162,7 +165,7 @@ func TestSortedRingBuffer_SizeZero_AddPanics(t *testing.T) {
 	}
 }
 
-// maxSize==0: RingBuffer.AddLast does not store the value, so the treemap must
+// maxSize==0: RingBuffer.AddLast does not store the value, so the tree must
 // not grow. Otherwise SortedSlice allocates by rb.count (0) then indexes past it.
 func TestSortedRingBuffer_SizeZero_SortedSliceStaysEmpty(t *testing.T) {
 	l := NewSortedRingBuffer[int](0)
@@ -197,3 +200,218 @@ func TestSortedRingBuffer_Filled(t *testing.T) {
 		t.Fatalf("at capacity Filled want true")
 	}
 }
+
+func ExampleSortedRingBuffer_Select() {
+	l := NewSortedRingBuffer[int](5)
+	for _, v := range []int{9, 3, 7, 1, 5, 8} {
+		l.AddLast(v)
+	}
+	// window is [3 7 1 5 8]
+	v, _ := l.Select(1)
+	fmt.Println(v, l.Rank(7), SortedRingBufferMedian(l), SortedRingBufferPercentile(l, 90, InterpolationLower))
+	// Output:
+	// 3 3 5 7
+}
+
+// Order statistics must follow evictions, including duplicates.
+func TestSortedRingBuffer_OrderStatistics(t *testing.T) {
+	l := NewSortedRingBuffer[float64](4)
+	if _, ok := l.Select(0); ok {
+		t.Fatalf("Select on empty want ok=false")
+	}
+	if m := SortedRingBufferMedian(l); !math.IsNaN(m) {
+		t.Fatalf("Median on empty want NaN got %v", m)
+	}
+	for _, v := range []float64{2, 2, 5, 1, 2, 4} {
+		l.AddLast(v)
+	}
+	// window is [5 1 2 4]
+	sorted := l.SortedSlice(nil)
+	for k, want := range sorted {
+		if got, ok := l.Select(k); !ok || got != want {
+			t.Fatalf("Select(%d) got %v want %v", k, got, want)
+		}
+	}
+	if got := l.Rank(2); got != 1 {
+		t.Fatalf("Rank(2) got %d want 1", got)
+	}
+	if got := l.Rank(10); got != 4 {
+		t.Fatalf("Rank(10) got %d want 4", got)
+	}
+	if got := SortedRingBufferMedian(l); got != 3 {
+		t.Fatalf("Median got %v want 3", got)
+	}
+	if got := SortedRingBufferPercentile(l, 25, InterpolationLinear); got != 1.75 {
+		t.Fatalf("Percentile(25) got %v want 1.75", got)
+	}
+}
+
+// assertSortedRingBuffer checks both the insertion order and that the sorted view stayed in sync with it.
+func assertSortedRingBuffer(t *testing.T, l *SortedRingBuffer[int], want []int) {
+	t.Helper()
+	if got := l.ToSlice(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ToSlice got %v want %v", got, want)
+	}
+	sorted := make([]int, len(want))
+	copy(sorted, want)
+	sort.Ints(sorted)
+	if got := l.SortedSlice(nil); !reflect.DeepEqual(got, sorted) {
+		t.Fatalf("SortedSlice got %v want %v", got, sorted)
+	}
+	if l.m.Len() != l.Count() {
+		t.Fatalf("sorted view holds %d elements, buffer holds %d", l.m.Len(), l.Count())
+	}
+}
+
+// Tests below mirror the RingBuffer resizing tests via NewSortedRingBufferConfigurable.
+
+func TestSortedRingBuffer_Grow_NoWrap(t *testing.T) {
+	size := 3
+	l := NewSortedRingBufferConfigurable[int](func() int { return size })
+	l.AddLast(1)
+	l.AddLast(2)
+	size = 5 // grow before wrap
+	l.AddLast(3)
+	l.AddLast(4)
+	l.AddLast(5)
+	assertSortedRingBuffer(t, l, []int{1, 2, 3, 4, 5})
+}
+
+func TestSortedRingBuffer_Grow_WithWrap(t *testing.T) {
+	size := 3
+	l := NewSortedRingBufferConfigurable[int](func() int { return size })
+	l.AddLast(1)
+	l.AddLast(2)
+	l.AddLast(3)
+	l.AddLast(4) // wraps, buffer now [2,3,4]
+	size = 6     // grow after wrap
+	l.AddLast(5)
+	assertSortedRingBuffer(t, l, []int{2, 3, 4, 5})
+}
+
+func TestSortedRingBuffer_Grow_WithWrap_TailLESizeDiff(t *testing.T) {
+	size := 5
+	l := NewSortedRingBufferConfigurable[int](func() int { return size })
+	for i := 1; i <= 6; i++ {
+		l.AddLast(i) // tail=1
+	}
+	size = 7 // sizeDiff=2
+	l.AddLast(7)
+	assertSortedRingBuffer(t, l, []int{2, 3, 4, 5, 6, 7})
+}
+
+func TestSortedRingBuffer_Grow_WithWrap_TailEqSizeDiff(t *testing.T) {
+	size := 5
+	l := NewSortedRingBufferConfigurable[int](func() int { return size })
+	for i := 1; i <= 7; i++ {
+		l.AddLast(i) // tail=2
+	}
+	size = 7 // sizeDiff=2
+	l.AddLast(8)
+	assertSortedRingBuffer(t, l, []int{3, 4, 5, 6, 7, 8})
+}
+
+func TestSortedRingBuffer_Grow_WithWrap_TailGtSizeDiff(t *testing.T) {
+	size := 5
+	l := NewSortedRingBufferConfigurable[int](func() int { return size })
+	for i := 1; i <= 8; i++ {
+		l.AddLast(i) // tail=3
+	}
+	size = 7 // sizeDiff=2
+	l.AddLast(9)
+	assertSortedRingBuffer(t, l, []int{4, 5, 6, 7, 8, 9})
+}
+
+func TestSortedRingBuffer_Shrink_FromFull(t *testing.T) {
+	size := 5
+	l := NewSortedRingBufferConfigurable[int](func() int { return size })
+	for i := 1; i <= 5; i++ {
+		l.AddLast(i)
+	}
+	size = 3
+	l.AddLast(6)
+	assertSortedRingBuffer(t, l, []int{4, 5, 6})
+}
+
+func TestSortedRingBuffer_Shrink_WhenSparse(t *testing.T) {
+	size := 5
+	l := NewSortedRingBufferConfigurable[int](func() int { return size })
+	l.AddLast(1)
+	l.AddLast(2)
+	size = 3 // shrink, still enough capacity
+	assertSortedRingBuffer(t, l, []int{1, 2})
+	l.AddLast(3)
+	assertSortedRingBuffer(t, l, []int{1, 2, 3})
+}
+
+func TestSortedRingBuffer_Shrink_KeepLatest(t *testing.T) {
+	size := 5
+	l := NewSortedRingBufferConfigurable[int](func() int { return size })
+	l.AddLast(1)
+	l.AddLast(2)
+	l.AddLast(3)
+	l.AddLast(4)
+	size = 3
+	l.AddLast(5)
+	assertSortedRingBuffer(t, l, []int{3, 4, 5})
+	l.AddLast(6)
+	assertSortedRingBuffer(t, l, []int{4, 5, 6})
+}
+
+func TestSortedRingBuffer_Shrink_ToZero(t *testing.T) {
+	size := 3
+	l := NewSortedRingBufferConfigurable[int](func() int { return size })
+	l.AddLast(1)
+	l.AddLast(2)
+	size = 0
+	l.AddLast(3)
+	assertSortedRingBuffer(t, l, []int{})
+	size = 2
+	l.AddLast(4)
+	assertSortedRingBuffer(t, l, []int{4})
+}
+
+func TestSortedRingBuffer_ResizeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	size := 4
+	l := NewSortedRingBufferConfigurable[int](func() int { return size })
+	var want []int
+	for i := 0; i < 2000; i++ {
+		if i%17 == 0 {
+			size = r.Intn(9)
+		}
+		v := r.Intn(10)
+		l.AddLast(v)
+		if size > 0 {
+			want = append(want, v)
+		}
+		if len(want) > size {
+			want = want[len(want)-size:]
+		}
+		assertSortedRingBuffer(t, l, append([]int{}, want...))
+	}
+}
+
+func TestSortedRingBuffer_OnEvict(t *testing.T) {
+	size := 3
+	rb := NewSortedRingBufferConfigurable[int](func() int { return size })
+	var evicted []int
+	rb.OnEvict(func(v int) { evicted = append(evicted, v) })
+
+	for _, v := range []int{5, 1, 4, 2} {
+		rb.AddLast(v)
+	}
+	size = 2
+	rb.AddLast(3)
+	if !reflect.DeepEqual(evicted, []int{5, 1, 4}) {
+		t.Fatalf("evicted want [5 1 4] got %v", evicted)
+	}
+	assertSortedRingBuffer(t, rb, []int{2, 3})
+
+	size = 0
+	rb.AddLast(6)
+	if !reflect.DeepEqual(evicted, []int{5, 1, 4, 2, 3, 6}) {
+		t.Fatalf("evicted want [5 1 4 2 3 6] got %v", evicted)
+	}
+	assertSortedRingBuffer(t, rb, []int{})
+}
@@ -0,0 +1,130 @@
+package quantainer
+
+import (
+	"math"
+	"time"
+)
+
+type (
+	// FixedDurationTWAP is the time-weighted average price over the last window, updated in O(1) per sample.
+	// Every price holds from its timestamp until the next sample, and the price of the last sample
+	// that expired holds at the start of the window. Samples must come in time order:
+	// an earlier timestamp than the latest one is clamped to it, so the sample replaces the latest price.
+	FixedDurationTWAP struct {
+		s        FixedDurationSlice[twapSample]
+		area     kahanSum // price * nanoseconds of the segments between the samples in s
+		boundary float64  // price of the last expired sample
+		expired  bool     // whether boundary is set
+	}
+
+	twapSample struct {
+		price float64
+		area  float64 // price * time until the next sample, once there is one
+	}
+)
+
+func NewFixedDurationTWAP(window time.Duration) *FixedDurationTWAP {
+	return NewFixedDurationTWAPWithClock(window, SystemClock)
+}
+
+// NewFixedDurationTWAPWithClock creates a FixedDurationTWAP that reads the current time from clock.
+func NewFixedDurationTWAPWithClock(window time.Duration, clock Clock) *FixedDurationTWAP {
+	result := &FixedDurationTWAP{
+		s: *NewFixedDurationSliceWithClock[twapSample](window, clock),
+	}
+	result.s.SetOutOfOrderPolicy(OutOfOrderClamp)
+	result.s.OnEvict(result.evict)
+	return result
+}
+
+func (me *FixedDurationTWAP) evict(v twapSample) {
+	me.area.add(-v.area)
+	me.boundary = v.price
+	me.expired = true
+}
+
+// Add adds price at the current time of the clock.
+func (me *FixedDurationTWAP) Add(price float64) {
+	me.AddAt(price, me.s.now())
+}
+
+// AddAt adds price at tm, and expires the samples that fell out of the window.
+func (me *FixedDurationTWAP) AddAt(price float64, tm time.Time) {
+	t := tm.UnixNano()
+	if n := len(me.s.t); n > 0 {
+		if last := me.s.t[n-1]; t > last {
+			prev := &me.s.l[n-1]
+			prev.area = prev.price * float64(t-last)
+			me.area.add(prev.area)
+		}
+	}
+	me.s.AddAt(twapSample{price: price}, tm)
+	me.resetIfEmpty()
+}
+
+// Expire drops the samples that ended before the window at now.
+func (me *FixedDurationTWAP) Expire(now time.Time) {
+	me.s.Expire(now)
+	me.resetIfEmpty()
+}
+
+func (me *FixedDurationTWAP) resetIfEmpty() {
+	if len(me.s.l) == 0 {
+		me.area.reset()
+	}
+}
+
+// Value returns the TWAP at the current time of the clock, see ValueAt.
+func (me *FixedDurationTWAP) Value() float64 {
+	return me.ValueAt(me.s.now())
+}
+
+// ValueAt expires the samples older than the window at now and returns the TWAP over the window ending at now.
+// Before the first sample expires, the window starts at the first sample. It returns NaN if there is no sample.
+func (me *FixedDurationTWAP) ValueAt(now time.Time) float64 {
+	me.Expire(now)
+	n := len(me.s.l)
+	if n == 0 {
+		if me.expired {
+			return me.boundary
+		}
+		return math.NaN()
+	}
+	end := now.UnixNano()
+	last := me.s.t[n-1]
+	if end < last {
+		end = last
+	}
+	area := me.area.sum + me.s.l[n-1].price*float64(end-last)
+	start := me.s.t[0]
+	if me.expired {
+		if cutoff := end - me.s.maxDuration; cutoff < start {
+			area += me.boundary * float64(start-cutoff)
+			start = cutoff
+		}
+	}
+	if end == start {
+		return me.s.l[n-1].price
+	}
+	return area / float64(end-start)
+}
+
+// Count returns the number of samples in the window.
+func (me *FixedDurationTWAP) Count() int {
+	return len(me.s.l)
+}
+
+// Last returns the latest price. ok is false if there is no sample in the window.
+func (me *FixedDurationTWAP) Last() (price float64, ok bool) {
+	if n := len(me.s.l); n > 0 {
+		return me.s.l[n-1].price, true
+	}
+	return
+}
+
+func (me *FixedDurationTWAP) Clear() {
+	me.s.Clear()
+	me.area.reset()
+	me.boundary = 0
+	me.expired = false
+}
@@ -0,0 +1,68 @@
+package quantainer
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func ExampleFixedDurationTWAP_Value() {
+	clock := NewManualClock(time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC))
+	twap := NewFixedDurationTWAPWithClock(10*time.Second, clock)
+	twap.Add(100) // holds for 2 seconds
+	clock.Advance(2 * time.Second)
+	twap.Add(110) // holds for 3 seconds until now
+	clock.Advance(3 * time.Second)
+	fmt.Println(twap.Value())
+	// Output:
+	// 106
+}
+
+func TestFixedDurationTWAP(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	at := func(seconds float64) time.Time { return start.Add(time.Duration(seconds * float64(time.Second))) }
+	twap := NewFixedDurationTWAPWithClock(4*time.Second, NewManualClock(start))
+	if !math.IsNaN(twap.Value()) {
+		t.Fatal("Expected NaN without samples")
+	}
+
+	twap.AddAt(10, at(0))
+	if v := twap.ValueAt(at(0)); v != 10 {
+		t.Fatalf("TWAP at the first sample want 10 got %v", v)
+	}
+	twap.AddAt(20, at(1))
+	twap.AddAt(30, at(3))
+	// 10 for 1s, 20 for 2s, 30 for 1s
+	if v := twap.ValueAt(at(4)); v != 20 {
+		t.Fatalf("TWAP at 4s want 20 got %v", v)
+	}
+	// the window [2, 6] has 20 for 1s, then 30 for 3s; only the last sample is left in it
+	if v := twap.ValueAt(at(6)); v != 27.5 || twap.Count() != 1 {
+		t.Fatalf("TWAP at 6s want 27.5 got %v with %d samples", v, twap.Count())
+	}
+	// the window [2.5, 6.5] has 20 for 0.5s, then 30 for 3.5s
+	if v := twap.ValueAt(at(6.5)); v != 28.75 {
+		t.Fatalf("TWAP at 6.5s want 28.75 got %v", v)
+	}
+	// all samples expired, the last price holds for the whole window
+	if v := twap.ValueAt(at(20)); v != 30 || twap.Count() != 0 {
+		t.Fatalf("TWAP at 20s want 30 got %v with %d samples", v, twap.Count())
+	}
+
+	// [18, 22]: 30 until 21, then 40
+	twap.AddAt(40, at(21))
+	if v := twap.ValueAt(at(22)); v != 32.5 {
+		t.Fatalf("TWAP at 22s want 32.5 got %v", v)
+	}
+	// a late sample is clamped to the latest timestamp, replacing the latest price
+	twap.AddAt(50, at(20.5))
+	if last, _ := twap.Last(); last != 50 || twap.ValueAt(at(22)) != 35 {
+		t.Fatalf("after a late sample want last 50 and TWAP 35, got %v and %v", last, twap.ValueAt(at(22)))
+	}
+
+	twap.Clear()
+	if !math.IsNaN(twap.ValueAt(at(22))) {
+		t.Fatal("Expected NaN after Clear")
+	}
+}
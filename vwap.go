@@ -0,0 +1,183 @@
+package quantainer
+
+import (
+	"math"
+	"time"
+)
+
+type (
+	// RollingVWAP is the volume-weighted average price of the last N trades, updated in O(1) per trade.
+	RollingVWAP struct {
+		rb vwapWindow[RingBuffer[vwapSample]]
+	}
+
+	// FixedDurationVWAP is the volume-weighted average price of the trades within the last window,
+	// updated in O(1) per trade, including expiry.
+	FixedDurationVWAP struct {
+		s vwapWindow[FixedDurationSlice[vwapSample]]
+	}
+
+	vwapSample struct {
+		price, volume float64
+	}
+
+	// vwapWindow holds the running sums of a VWAP next to the container of its samples.
+	vwapWindow[C any] struct {
+		c        C
+		notional kahanSum // sum of price * volume
+		volume   kahanSum
+		count    int
+	}
+)
+
+func (me *vwapWindow[C]) add(v vwapSample) {
+	me.notional.add(v.price * v.volume)
+	me.volume.add(v.volume)
+	me.count++
+}
+
+func (me *vwapWindow[C]) evict(v vwapSample) {
+	me.count--
+	if me.count == 0 {
+		me.reset()
+		return
+	}
+	me.notional.add(-v.price * v.volume)
+	me.volume.add(-v.volume)
+}
+
+func (me *vwapWindow[C]) reset() {
+	me.notional.reset()
+	me.volume.reset()
+	me.count = 0
+}
+
+// value returns the VWAP, or NaN if there is no volume.
+func (me *vwapWindow[C]) value() float64 {
+	if me.count == 0 || me.volume.sum == 0 {
+		return math.NaN()
+	}
+	return me.notional.sum / me.volume.sum
+}
+
+func NewRollingVWAP(size int) *RollingVWAP {
+	result := &RollingVWAP{}
+	result.rb.c = NewRingBuffer[vwapSample](size)
+	return result
+}
+
+// NewRollingVWAPConfigurable creates a new VWAP window with a configurable number of trades.
+// If maxSize shrinks at runtime, the oldest trades are removed on the next AddLast.
+func NewRollingVWAPConfigurable(maxSize func() int) *RollingVWAP {
+	result := &RollingVWAP{}
+	result.rb.c = *NewRingBufferConfigurable[vwapSample](maxSize)
+	return result
+}
+
+// AddLast adds a trade. If the window is full, the oldest trade is evicted.
+func (me *RollingVWAP) AddLast(price, volume float64) {
+	v := vwapSample{price, volume}
+	if me.rb.c.addLast(v, me.rb.evict) {
+		me.rb.add(v)
+	}
+}
+
+// Value returns the VWAP of the trades in the window, or NaN if their volume is 0.
+func (me *RollingVWAP) Value() float64 {
+	return me.rb.value()
+}
+
+// Volume returns the total volume of the trades in the window.
+func (me *RollingVWAP) Volume() float64 {
+	return me.rb.volume.sum
+}
+
+func (me *RollingVWAP) Count() int {
+	return me.rb.c.count
+}
+
+func (me *RollingVWAP) MaxSize() int {
+	return me.rb.c.maxSize()
+}
+
+// Full returns true if the window's max size is reached.
+func (me *RollingVWAP) Full() bool {
+	return me.rb.c.Full()
+}
+
+// Filled is the same as Full except it returns false if the window's size is 0
+func (me *RollingVWAP) Filled() bool {
+	return me.rb.c.Filled()
+}
+
+func (me *RollingVWAP) Clear() {
+	me.rb.c.Clear()
+	me.rb.reset()
+}
+
+func NewFixedDurationVWAP(window time.Duration) *FixedDurationVWAP {
+	return NewFixedDurationVWAPWithClock(window, SystemClock)
+}
+
+// NewFixedDurationVWAPWithClock creates a FixedDurationVWAP whose Add timestamps trades with clock.
+// Late trades are clamped to the latest timestamp (OutOfOrderClamp) to keep updates O(1);
+// OutOfOrderInsert expires them on time, but costs O(n) per late trade.
+func NewFixedDurationVWAPWithClock(window time.Duration, clock Clock) *FixedDurationVWAP {
+	result := &FixedDurationVWAP{}
+	result.s.c = *NewFixedDurationSliceWithClock[vwapSample](window, clock)
+	result.s.c.SetOutOfOrderPolicy(OutOfOrderClamp)
+	result.s.c.OnEvict(result.s.evict)
+	return result
+}
+
+// SetOutOfOrderPolicy sets what AddAt does with timestamps earlier than the latest one.
+func (me *FixedDurationVWAP) SetOutOfOrderPolicy(policy OutOfOrderPolicy) {
+	me.s.c.SetOutOfOrderPolicy(policy)
+}
+
+// Add adds a trade at the current time of the clock.
+func (me *FixedDurationVWAP) Add(price, volume float64) {
+	me.AddAt(price, volume, me.s.c.now())
+}
+
+// AddAt adds a trade at tm like FixedDurationSlice.AddAt, and returns false if it was not stored.
+func (me *FixedDurationVWAP) AddAt(price, volume float64, tm time.Time) bool {
+	v := vwapSample{price, volume}
+	if !me.s.c.AddAt(v, tm) {
+		return false
+	}
+	me.s.add(v)
+	return true
+}
+
+// Expire drops the trades older than the window at now.
+func (me *FixedDurationVWAP) Expire(now time.Time) {
+	me.s.c.Expire(now)
+}
+
+// Value returns the VWAP at the current time of the clock, see ValueAt.
+func (me *FixedDurationVWAP) Value() float64 {
+	return me.ValueAt(me.s.c.now())
+}
+
+// ValueAt expires the trades older than the window at now and returns the VWAP of the rest,
+// or NaN if their volume is 0.
+func (me *FixedDurationVWAP) ValueAt(now time.Time) float64 {
+	me.Expire(now)
+	return me.s.value()
+}
+
+// Volume returns the total volume of the trades in the window as of the last AddAt, Expire or ValueAt.
+func (me *FixedDurationVWAP) Volume() float64 {
+	return me.s.volume.sum
+}
+
+// Count returns the number of trades in the window as of the last AddAt, Expire or ValueAt.
+func (me *FixedDurationVWAP) Count() int {
+	return me.s.count
+}
+
+func (me *FixedDurationVWAP) Clear() {
+	me.s.c.Clear()
+	me.s.reset()
+}
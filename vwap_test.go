@@ -0,0 +1,133 @@
+package quantainer
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func ExampleRollingVWAP_Value() {
+	vwap := NewRollingVWAP(2)
+	vwap.AddLast(100, 1)
+	vwap.AddLast(102, 3)
+	vwap.AddLast(101, 1)
+	fmt.Println(vwap.Value(), vwap.Volume())
+	// Output:
+	// 101.75 4
+}
+
+func TestRollingVWAP(t *testing.T) {
+	size := 5
+	vwap := NewRollingVWAPConfigurable(func() int { return size })
+	if !math.IsNaN(vwap.Value()) {
+		t.Fatal("Expected NaN VWAP without trades")
+	}
+	rnd := rand.New(rand.NewSource(1))
+	var prices, volumes []float64
+	for step := 0; step < 1000; step++ {
+		if step%100 == 0 {
+			size = 1 + rnd.Intn(8)
+		}
+		p, v := 100+rnd.Float64(), float64(1+rnd.Intn(10))
+		vwap.AddLast(p, v)
+		prices, volumes = append(prices, p), append(volumes, v)
+		if len(prices) > size {
+			prices, volumes = prices[len(prices)-size:], volumes[len(volumes)-size:]
+		}
+
+		notional, volume := 0.0, 0.0
+		for i := range prices {
+			notional += prices[i] * volumes[i]
+			volume += volumes[i]
+		}
+		if vwap.Count() != len(prices) || math.Abs(vwap.Value()-notional/volume) > 1e-9 || math.Abs(vwap.Volume()-volume) > 1e-9 {
+			t.Fatalf("step %d: VWAP %v want %v, volume %v want %v", step, vwap.Value(), notional/volume, vwap.Volume(), volume)
+		}
+	}
+	if !vwap.Full() || !vwap.Filled() || vwap.MaxSize() != size {
+		t.Fatal("Expected a full window")
+	}
+
+	size = 0
+	vwap.AddLast(1, 1)
+	if vwap.Count() != 0 || !math.IsNaN(vwap.Value()) {
+		t.Fatalf("Expected an empty window of size 0, got %d trades", vwap.Count())
+	}
+	size = 2
+	vwap.AddLast(10, 1)
+	if vwap.Value() != 10 {
+		t.Fatalf("VWAP want 10 got %v", vwap.Value())
+	}
+	vwap.Clear()
+	if vwap.Count() != 0 || vwap.Volume() != 0 {
+		t.Fatal("Expected an empty window after Clear")
+	}
+}
+
+func TestFixedDurationVWAP(t *testing.T) {
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	vwap := NewFixedDurationVWAPWithClock(3*time.Second, clock)
+	vwap.Add(100, 2)
+	clock.Advance(time.Second)
+	vwap.Add(103, 1)
+	if vwap.Value() != 101 || vwap.Volume() != 3 || vwap.Count() != 2 {
+		t.Fatalf("VWAP want 101 got %v", vwap.Value())
+	}
+
+	// by default a late trade is clamped to the latest timestamp, and expires with it
+	vwap.AddAt(106, 1, start.Add(-time.Second))
+	if vwap.Value() != 102.25 {
+		t.Fatalf("VWAP want 102.25 got %v", vwap.Value())
+	}
+	vwap.Expire(start.Add(3500 * time.Millisecond))
+	if vwap.Value() != 104.5 || vwap.Count() != 2 {
+		t.Fatalf("after Expire VWAP want 104.5 got %v", vwap.Value())
+	}
+
+	// with OutOfOrderInsert a late trade is inserted in time order, and expires with its own timestamp
+	vwap.Clear()
+	vwap.SetOutOfOrderPolicy(OutOfOrderInsert)
+	vwap.AddAt(100, 2, start)
+	vwap.AddAt(103, 1, start.Add(time.Second))
+	vwap.AddAt(106, 1, start.Add(-time.Second))
+	if vwap.Value() != 102.25 {
+		t.Fatalf("VWAP want 102.25 got %v", vwap.Value())
+	}
+	vwap.Expire(start.Add(2500 * time.Millisecond))
+	if vwap.Value() != 101 || vwap.Count() != 2 {
+		t.Fatalf("after Expire VWAP want 101 got %v", vwap.Value())
+	}
+	vwap.Expire(start.Add(5 * time.Second))
+	if !math.IsNaN(vwap.Value()) || vwap.Volume() != 0 || vwap.Count() != 0 {
+		t.Fatalf("Expected an empty window, got %v", vwap.Value())
+	}
+
+	// trading stops: the trades expire as the clock moves past the window
+	vwap.Clear()
+	vwap.AddAt(100, 1, clock.Now())
+	clock.Advance(2 * time.Second)
+	if vwap.Value() != 100 {
+		t.Fatalf("VWAP within the window want 100 got %v", vwap.Value())
+	}
+	clock.Advance(2 * time.Second)
+	if !math.IsNaN(vwap.Value()) || vwap.Count() != 0 {
+		t.Fatalf("Expected the trade to expire with the clock, got %v", vwap.Value())
+	}
+	vwap.AddAt(100, 1, clock.Now())
+	if v := vwap.ValueAt(clock.Now().Add(4 * time.Second)); !math.IsNaN(v) || vwap.Count() != 0 {
+		t.Fatalf("Expected the trade to expire at ValueAt, got %v", v)
+	}
+
+	vwap.SetOutOfOrderPolicy(OutOfOrderReject)
+	vwap.AddAt(100, 1, start.Add(10*time.Second))
+	if vwap.AddAt(101, 1, start.Add(9*time.Second)) || vwap.Count() != 1 {
+		t.Fatal("Expected the late trade to be rejected")
+	}
+	vwap.Clear()
+	if vwap.Count() != 0 || vwap.Volume() != 0 {
+		t.Fatal("Expected an empty window after Clear")
+	}
+}